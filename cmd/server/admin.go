@@ -0,0 +1,53 @@
+// ============================================================================
+// cmd/server/admin.go - 관리 엔드포인트 (실행 중 로그 레벨 변경)
+// ============================================================================
+// 라인에서 디버깅할 때 서버를 다시 시작하지 않고 로그 레벨을 올리고
+// 끝나면 되돌립니다. 변경은 프로세스가 끝나면 사라집니다 (설정 파일은 그대로).
+//
+//	GET /api/admin/loglevel                     현재 레벨과 형식
+//	PUT /api/admin/loglevel {"level":"DEBUG"}   레벨 변경 (이전 레벨 반환)
+// ============================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// logLevelHandler 로그 레벨 조회(GET) 및 변경(PUT)
+func (s *apiServer) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	resp := types.LogLevelResponse{}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req types.LogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		level, err := types.ParseLogLevel(req.Level)
+		if err != nil {
+			http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		previous := logging.SetLevel(level)
+		resp.Previous = &previous
+		// 낮추는 경우에도 남도록 INFO로 기록
+		logging.Log(types.LogLevelInfo, traceID(r, req.Meta), logging.Fields{"previous": previous, "client": clientID(r, req.Meta)},
+			"📝 로그 레벨 변경: %s → %s", previous, level)
+	default:
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp.Level = logging.Level()
+	resp.Format = logging.Format()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
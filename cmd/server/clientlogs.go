@@ -0,0 +1,187 @@
+// ============================================================================
+// cmd/server/clientlogs.go - 브라우저 클라이언트 로그 수신 및 조회
+// ============================================================================
+// POST /client-log                    로그 하나 또는 배열 (types.ClientLogRequest)
+// GET  /api/client-logs?session=&trace=&level=&since=&limit=
+//
+// - session: RequestMeta.SessionID (작업자 화면 하나)
+// - trace: RequestMeta.TraceID (서버 로그의 trace_id와 같은 값)
+// - level: 이 레벨 이상 (error, warn, info, debug)
+// - since: RFC 3339 시각, 유닉스 밀리초, 또는 지금 기준 음수 기간 ("-30m")
+// - limit: 최근 항목부터 최대 개수 (기본 1000)
+//
+// 받은 로그는 client_log.directory의 회전 파일에 저장하고, error 레벨은
+// 서버 로그에도 남깁니다.
+// ============================================================================
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/clientlog"
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 클라이언트 로그 수신 제한
+const (
+	CLIENT_LOG_MAX_BODY  = 256 << 10 // 요청 본문 최대 크기
+	CLIENT_LOG_MAX_BATCH = 100       // 요청 하나에 담을 수 있는 로그 수
+)
+
+// clientLogHandler 브라우저 클라이언트 로그 수신 및 저장
+func (s *apiServer) clientLogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	reqs, err := decodeClientLogs(http.MaxBytesReader(w, r.Body, CLIENT_LOG_MAX_BODY))
+	if err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339Nano)
+	entries := make([]types.ClientLogEntry, 0, len(reqs))
+	for _, req := range reqs {
+		level, err := clientlog.ParseLevel(req.Level)
+		if err != nil {
+			http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		userAgent := req.Meta.UserAgent
+		if userAgent == "" {
+			userAgent = r.UserAgent()
+		}
+		entries = append(entries, types.ClientLogEntry{
+			Time:       now,
+			ClientTime: req.Timestamp,
+			Level:      level,
+			Message:    req.Message,
+			SessionID:  req.Meta.SessionID,
+			TraceID:    traceID(r, req.Meta),
+			ClientID:   req.Meta.ClientID,
+			UserAgent:  userAgent,
+			RemoteAddr: r.RemoteAddr,
+			Fields:     req.Fields,
+		})
+	}
+
+	if err := s.clientLogs.Append(entries); err != nil {
+		logging.Info("❌ 클라이언트 로그 저장 실패: %v", err)
+		http.Error(w, "클라이언트 로그 저장 실패", http.StatusInternalServerError)
+		return
+	}
+	for _, entry := range entries {
+		logClientEntry(entry)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeClientLogs 로그 하나 또는 배열 디코딩
+func decodeClientLogs(body io.Reader) ([]types.ClientLogRequest, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var reqs []types.ClientLogRequest
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(raw, &reqs); err != nil {
+			return nil, err
+		}
+	} else {
+		var req types.ClientLogRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+
+	switch {
+	case len(reqs) == 0:
+		return nil, errors.New("로그가 없습니다")
+	case len(reqs) > CLIENT_LOG_MAX_BATCH:
+		return nil, errors.New("요청 하나에 최대 " + strconv.Itoa(CLIENT_LOG_MAX_BATCH) + "개까지 보낼 수 있습니다")
+	}
+	return reqs, nil
+}
+
+// logClientEntry 서버 로그에도 남김 (error는 INFO, 나머지는 DEBUG)
+func logClientEntry(entry types.ClientLogEntry) {
+	level := types.LogLevelDebug
+	if entry.Level == clientlog.LevelError {
+		level = types.LogLevelInfo
+	}
+	fields := logging.Fields{"client_level": entry.Level}
+	if entry.SessionID != "" {
+		fields["session"] = entry.SessionID
+	}
+	if entry.ClientID != "" {
+		fields["client"] = entry.ClientID
+	}
+	logging.Log(level, entry.TraceID, fields, "🌐 클라이언트 로그: %s", entry.Message)
+}
+
+// clientLogsHandler 저장된 클라이언트 로그 조회 (오래된 순서)
+func (s *apiServer) clientLogsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := parseClientLogQuery(r, time.Now())
+	if err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := s.clientLogs.Query(q)
+	if errors.Is(err, clientlog.ErrInvalidQuery) {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []types.ClientLogEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// parseClientLogQuery 쿼리 문자열을 조회 조건으로 변환
+func parseClientLogQuery(r *http.Request, now time.Time) (clientlog.Query, error) {
+	values := r.URL.Query()
+	q := clientlog.Query{
+		SessionID: values.Get("session"),
+		TraceID:   values.Get("trace"),
+	}
+
+	var err error
+	if v := values.Get("level"); v != "" {
+		if q.Level, err = clientlog.ParseLevel(v); err != nil {
+			return q, err
+		}
+	}
+	if q.Since, err = parseHistoryTime(values.Get("since"), now); err != nil {
+		return q, errors.New("since " + err.Error())
+	}
+	if v := values.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			return q, errors.New("limit은 정수여야 합니다")
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,117 @@
+// ============================================================================
+// cmd/server/events.go - Server-Sent Events 피드 (/api/events)
+// ============================================================================
+// WebSocket을 유지할 수 없는 프록시 뒤의 대시보드/키오스크 브라우저용
+// 단방향 이벤트 스트림입니다. EventSource가 다시 연결할 때 보내는
+// Last-Event-ID 이후의 이벤트를 메모리 버퍼에서 재전송합니다.
+//
+// 형식 (이벤트마다):
+//
+//	id: 42
+//	event: mode_changed
+//	data: {"id":42,"type":"mode_changed","time":"...","data":{...}}
+// ============================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// SSE 설정 상수
+const (
+	SSE_KEEPALIVE_INTERVAL = 15 * time.Second // 프록시 유휴 시간 초과 방지용 주석 전송 주기
+	SSE_RETRY_MS           = 2000             // 브라우저 재연결 대기 시간 (retry 필드)
+	MSG_SSE_UNSUPPORTED    = "Streaming unsupported"
+)
+
+// eventsHandler 로봇 이벤트 SSE 스트림
+// Last-Event-ID 헤더(또는 ?lastEventId=)가 있으면 그 이후의 보관된 이벤트부터 전송합니다.
+func (s *apiServer) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, MSG_SSE_UNSUPPORTED, http.StatusInternalServerError)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	var since uint64
+	if lastID != "" {
+		var err error
+		if since, err = strconv.ParseUint(lastID, 10, 64); err != nil {
+			http.Error(w, MSG_BAD_REQUEST+": Last-Event-ID "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	replay, complete, events, unsubscribe := s.events.Subscribe(since)
+	defer unsubscribe()
+	atomic.AddInt32(&s.sseClients, 1)
+	defer atomic.AddInt32(&s.sseClients, -1)
+	unwatch := s.state.Watch() // state 이벤트를 받는 동안 유휴 주기로 늦추지 않음
+	defer unwatch()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx 응답 버퍼링 끄기
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", SSE_RETRY_MS)
+	if !complete {
+		// 일부 이벤트가 버퍼에서 밀려남 - 클라이언트는 전체 상태를 다시 받아야 함
+		fmt.Fprintf(w, ": replay incomplete since %d\n\n", since)
+	}
+	for _, event := range replay {
+		if err := writeSSEEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(SSE_KEEPALIVE_INTERVAL)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return // 너무 느린 구독자 - 다시 연결하면 Last-Event-ID로 이어 받음
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSEEvent 이벤트 하나를 SSE 형식으로 기록 (JSON은 한 줄이므로 data 한 줄)
+func writeSSEEvent(w http.ResponseWriter, event types.RobotEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,467 @@
+// ============================================================================
+// cmd/server/handlers.go - Virtual Pendant API 핸들러
+// ============================================================================
+// REST API 요청을 처리하는 핸들러들입니다.
+// 로봇 컨트롤러는 apiServer에 주입되므로 실제 컨트롤러, 시뮬레이터,
+// 테스트용 가짜 구현을 같은 핸들러로 사용할 수 있습니다.
+// ============================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/clientlog"
+	"github.com/nir414/go-virtual-pendant/internal/config"
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// API 서버 (API Server)
+// ============================================================================
+
+// apiServer API 핸들러가 공유하는 의존성
+type apiServer struct {
+	ctrl     robot.Controller
+	state    *robot.StateBroker
+	model    *robot.Model
+	sessions *robot.JogSessionManager
+	allStop  *robot.AllStop
+	events   *robot.EventBus
+	history  *robot.History
+	recorder *robot.Recorder
+
+	clientLogs *clientlog.Store
+
+	sseClients int32 // 접속 중인 SSE 클라이언트 수 (atomic)
+}
+
+// newAPIServer 컨트롤러, 상태 브로커, 로봇 모델, JOG 세션 관리자, 전체 정지 실행기, 이벤트 버스, 위치 이력, 궤적 기록 관리자, 클라이언트 로그 저장소를 주입받아 apiServer 생성
+func newAPIServer(ctrl robot.Controller, state *robot.StateBroker, model *robot.Model, sessions *robot.JogSessionManager, allStop *robot.AllStop, events *robot.EventBus, history *robot.History, recorder *robot.Recorder, clientLogs *clientlog.Store) *apiServer {
+	return &apiServer{ctrl: ctrl, state: state, model: model, sessions: sessions, allStop: allStop, events: events, history: history, recorder: recorder, clientLogs: clientLogs}
+}
+
+// registerRoutes API 엔드포인트를 mux에 등록
+func (s *apiServer) registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc(ENDPOINT_JOG, s.jogHandler)
+	mux.HandleFunc(ENDPOINT_JOG_STATE, s.jogStateHandler)
+	mux.HandleFunc(ENDPOINT_JOG_MODE, s.setJogModeHandler)
+	mux.HandleFunc(ENDPOINT_JOG_AXIS, s.setAxisHandler)
+	mux.HandleFunc(ENDPOINT_CONNECTION, s.connectionHandler)
+	mux.HandleFunc(ENDPOINT_MODEL, s.modelHandler)
+	mux.HandleFunc(ENDPOINT_JOG_SESSIONS, s.jogSessionsHandler)
+	mux.HandleFunc(ENDPOINT_JOG_SESSION_START, s.jogSessionStartHandler)
+	mux.HandleFunc(ENDPOINT_JOG_SESSION_HEARTBEAT, s.jogSessionHeartbeatHandler)
+	mux.HandleFunc(ENDPOINT_JOG_SESSION_STOP, s.jogSessionStopHandler)
+	mux.HandleFunc(ENDPOINT_STOP, s.stopHandler)
+	mux.HandleFunc(ENDPOINT_STOP_LOG, s.stopLogHandler)
+	mux.HandleFunc(ENDPOINT_EVENTS, s.eventsHandler)
+	mux.HandleFunc(ENDPOINT_HISTORY, s.historyHandler)
+	mux.HandleFunc(ENDPOINT_RECORDINGS, s.recordingsHandler)
+	mux.HandleFunc(ENDPOINT_RECORDING_START, s.recordingStartHandler)
+	mux.HandleFunc(ENDPOINT_RECORDING_STOP, s.recordingStopHandler)
+	mux.HandleFunc(ENDPOINT_RECORDING_DOWNLOAD, s.recordingDownloadHandler)
+	mux.HandleFunc(ENDPOINT_ADMIN_LOG_LEVEL, s.logLevelHandler)
+	mux.HandleFunc(ENDPOINT_CLIENT_LOG, s.clientLogHandler)
+	mux.HandleFunc(ENDPOINT_CLIENT_LOGS, s.clientLogsHandler)
+}
+
+// ============================================================================
+// 응답 헬퍼 (Response Helpers)
+// ============================================================================
+
+// statusForErrorCode JogResponse.ErrorCode를 HTTP 상태 코드로 변환
+func statusForErrorCode(code string) int {
+	switch code {
+	case "":
+		return http.StatusOK
+	case types.ErrCodeInvalidCommand:
+		return http.StatusBadRequest
+	case types.ErrCodeRejected, types.ErrCodeStaleCommand, types.ErrCodeSoftLimit:
+		return http.StatusConflict
+	case types.ErrCodeSessionNotFound:
+		return http.StatusNotFound
+	case types.ErrCodeQueueFull, types.ErrCodeStateUnavailable, types.ErrCodeStaleData:
+		return http.StatusServiceUnavailable
+	case types.ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		// 연결 실패, 컨트롤러 HTTP 오류, 인증/리다이렉트 문제
+		return http.StatusBadGateway
+	}
+}
+
+// writeJogResponse JogResponse를 ErrorCode에 맞는 상태 코드와 함께 JSON으로 전송
+func writeJogResponse(w http.ResponseWriter, response *types.JogResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusForErrorCode(response.ErrorCode))
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeSessionResponse JogSessionResponse를 ErrorCode에 맞는 상태 코드와 함께 JSON으로 전송
+func writeSessionResponse(w http.ResponseWriter, response *types.JogSessionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusForErrorCode(response.ErrorCode))
+	json.NewEncoder(w).Encode(response)
+}
+
+// sessionNotFoundResponse 없거나 이미 종료된 세션 응답 (종료 사유 포함)
+func sessionNotFoundResponse(info types.JogSessionInfo) *types.JogSessionResponse {
+	msg := "JOG 세션을 찾을 수 없습니다"
+	if info.EndReason != "" {
+		msg = "JOG 세션이 이미 종료되었습니다: " + info.EndReason
+	}
+	return &types.JogSessionResponse{
+		Success:   false,
+		Message:   msg,
+		Session:   &info,
+		ErrorCode: types.ErrCodeSessionNotFound,
+	}
+}
+
+// clientID 요청 메타데이터의 클라이언트 식별자 (없으면 원격 주소)
+func clientID(r *http.Request, meta types.RequestMeta) string {
+	if meta.ClientID != "" {
+		return meta.ClientID
+	}
+	return r.RemoteAddr
+}
+
+// traceID 요청 추적 ID (meta.TraceID, 없으면 X-Trace-ID 헤더)
+func traceID(r *http.Request, meta types.RequestMeta) string {
+	if meta.TraceID != "" {
+		return meta.TraceID
+	}
+	return r.Header.Get(HEADER_TRACE_ID)
+}
+
+// logCommand API 명령 처리 결과 로그 (디버그 레벨, 지연은 큐 대기 포함)
+func logCommand(action, trace string, fields logging.Fields, start time.Time, resp *types.JogResponse) {
+	if !logging.Enabled(types.LogLevelDebug) {
+		return
+	}
+	if fields == nil {
+		fields = logging.Fields{}
+	}
+	fields["action"] = action
+	fields["latency_ms"] = logging.Millis(time.Since(start))
+	if resp != nil {
+		fields["success"] = resp.Success
+		if resp.ErrorCode != "" {
+			fields["error_code"] = resp.ErrorCode
+		}
+	}
+	logging.Log(types.LogLevelDebug, trace, fields, "API 명령 처리: %s", action)
+}
+
+// ============================================================================
+// API 핸들러 함수들 (API Handlers)
+// ============================================================================
+
+// jogHandler JOG 명령 요청 처리
+func (s *apiServer) jogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var cmd types.JogCommand
+	err := json.NewDecoder(r.Body).Decode(&cmd)
+	if err != nil {
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 로봇에 JOG 명령 전송 (추적 ID는 컨트롤러 로그까지 전달)
+	cmd.Meta.TraceID = traceID(r, cmd.Meta)
+	start := time.Now()
+	response, _ := s.ctrl.SendJogCommand(cmd)
+	logCommand("jog", cmd.Meta.TraceID, logging.Fields{"axis": cmd.Axis, "dir": cmd.Dir, "mode": cmd.Mode}, start, response)
+	writeJogResponse(w, response)
+}
+
+// jogStateHandler 로봇 상태 조회 요청 처리 (상태 브로커 캐시에서 응답)
+// ?maxAge=250ms (또는 밀리초 숫자)를 주면 그보다 오래된 상태는 컨트롤러에서 다시 읽습니다.
+func (s *apiServer) jogStateHandler(w http.ResponseWriter, r *http.Request) {
+	var maxAge time.Duration
+	if v := r.URL.Query().Get("maxAge"); v != "" {
+		var err error
+		if maxAge, err = config.ParseMillis(v); err != nil {
+			http.Error(w, MSG_BAD_REQUEST+": maxAge "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 마지막 조회가 실패했으면 이전 상태 대신 오류 응답 (연결 끊김을 숨기지 않음)
+	snap := s.state.Get(maxAge)
+	if snap.Err != nil || snap.State == nil {
+		http.Error(w, MSG_FETCH_STATE_FAILED, http.StatusBadGateway)
+		return
+	}
+
+	// 공유 상태는 수정하지 않고 복사본에 나이만 기록
+	data := *snap.State
+	data.Meta.AgeMs = snap.Age().Milliseconds()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&data)
+}
+
+// setJogModeHandler JOG 모드 변경 요청 처리
+func (s *apiServer) setJogModeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.SetJogModeRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	response, _ := s.ctrl.SetJogMode(req.Mode)
+	logCommand("mode", traceID(r, req.Meta), logging.Fields{"mode": req.Mode}, start, response)
+	writeJogResponse(w, response)
+}
+
+// setAxisHandler 축 선택 요청 처리
+func (s *apiServer) setAxisHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.SetAxisRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	response, _ := s.ctrl.SetAxis(req.Axis, req.Robot)
+	logCommand("axis", traceID(r, req.Meta), logging.Fields{"axis": req.Axis, "robot": req.Robot}, start, response)
+	writeJogResponse(w, response)
+}
+
+// connectionHandler 컨트롤러 연결 상태 조회
+func (s *apiServer) connectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.ctrl.ConnectionStatus())
+}
+
+// modelHandler 로봇 모델 조회 (UI의 축/모드 목록 생성용)
+func (s *apiServer) modelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.model.Info())
+}
+
+// ============================================================================
+// 연속 JOG 세션 핸들러 (Jog Session Handlers)
+// ============================================================================
+
+// jogSessionsHandler 활성 세션과 최근 종료된 세션 조회
+func (s *apiServer) jogSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]types.JogSessionInfo{
+		"active": s.sessions.Active(),
+		"recent": s.sessions.Recent(),
+	})
+}
+
+// jogSessionStartHandler 연속 JOG 세션 시작 (서버가 반복 전송 담당)
+func (s *apiServer) jogSessionStartHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.JogSessionStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Meta.TraceID = traceID(r, req.Meta) // 세션이 반복 전송하는 명령 로그에도 기록
+	writeSessionResponse(w, s.startJogSession(req.JogCommand, clientID(r, req.Meta)))
+}
+
+// jogSessionHeartbeatHandler 세션 하트비트 (데드맨 타이머 갱신)
+func (s *apiServer) jogSessionHeartbeatHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.JogSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeSessionResponse(w, s.heartbeatJogSession(req.SessionID))
+}
+
+// jogSessionStopHandler 세션 중단 (중단 명령 전송 후 응답)
+func (s *apiServer) jogSessionStopHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.JogSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeSessionResponse(w, s.stopJogSession(req.SessionID))
+}
+
+// startJogSession 연속 JOG 세션 시작 (HTTP와 WebSocket 공용)
+func (s *apiServer) startJogSession(cmd types.JogCommand, owner string) *types.JogSessionResponse {
+	response := s.doStartJogSession(cmd, owner)
+	s.publishSessionCommand("jog_session_start", cmd, response)
+	return response
+}
+
+// doStartJogSession 세션 시작 및 응답 생성
+func (s *apiServer) doStartJogSession(cmd types.JogCommand, owner string) *types.JogSessionResponse {
+	info, resp, err := s.sessions.Start(cmd, owner)
+	if err != nil {
+		return &types.JogSessionResponse{
+			Success:   false,
+			Message:   resp.Message,
+			ErrorCode: resp.ErrorCode,
+		}
+	}
+
+	return &types.JogSessionResponse{
+		Success: true,
+		Message: fmt.Sprintf("연속 JOG 시작: %s %s (하트비트 %dms 이내 필요)", info.Command.Axis, info.Command.Dir, info.HeartbeatTimeoutMs),
+		Session: &info,
+	}
+}
+
+// heartbeatJogSession 세션 하트비트 (HTTP와 WebSocket 공용)
+func (s *apiServer) heartbeatJogSession(id string) *types.JogSessionResponse {
+	info, err := s.sessions.Heartbeat(id)
+	if errors.Is(err, robot.ErrJogSessionNotFound) {
+		return sessionNotFoundResponse(info)
+	}
+	return &types.JogSessionResponse{Success: true, Message: "OK", Session: &info}
+}
+
+// stopJogSession 세션 중단 (HTTP와 WebSocket 공용)
+func (s *apiServer) stopJogSession(id string) *types.JogSessionResponse {
+	var response *types.JogSessionResponse
+	info, err := s.sessions.Stop(id)
+	if errors.Is(err, robot.ErrJogSessionNotFound) {
+		response = sessionNotFoundResponse(info)
+	} else {
+		response = &types.JogSessionResponse{
+			Success: true,
+			Message: fmt.Sprintf("연속 JOG 중단 (전송 %d회)", info.CommandsSent),
+			Session: &info,
+		}
+	}
+	s.publishSessionCommand("jog_session_stop", map[string]string{"session_id": id}, response)
+	return response
+}
+
+// publishSessionCommand 세션 시작/중단을 command 이벤트로 게시 (하트비트는 제외)
+func (s *apiServer) publishSessionCommand(action string, request interface{}, response *types.JogSessionResponse) {
+	s.events.Publish(robot.EventCommand, types.CommandEvent{
+		Action:    action,
+		Request:   request,
+		Success:   response.Success,
+		Message:   response.Message,
+		ErrorCode: response.ErrorCode,
+	})
+}
+
+// ============================================================================
+// 전체 정지 핸들러 (All-Stop Handlers)
+// ============================================================================
+
+// stopHandler 전체 정지 (대기/반복 명령 취소 후 중단 명령 즉시 전송)
+func (s *apiServer) stopHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 본문이 없거나 잘못되어도 정지는 반드시 실행
+	var req types.StopRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		req = types.StopRequest{Reason: "본문 파싱 실패: " + err.Error()}
+	}
+
+	record := s.allStop.Trigger(req, r.RemoteAddr, r.UserAgent())
+	message := record.Message
+	if record.DisableRequested {
+		message += " / JOG 비활성화: " + record.DisableMessage
+	}
+	s.events.Publish(robot.EventCommand, types.CommandEvent{
+		Action:    "all_stop",
+		Request:   req,
+		Success:   record.Success,
+		Message:   message,
+		ErrorCode: record.ErrorCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(stopStatus(record))
+	json.NewEncoder(w).Encode(record)
+}
+
+// stopStatus 전체 정지 응답 상태 코드
+// 로봇이 멈췄는지가 우선이므로 중단 명령 결과로 정합니다. 중단은 성공했지만
+// JOG 비활성화만 실패하면 200으로 응답하고 jog_disabled/disable_error_code로 알립니다.
+func stopStatus(record types.StopRecord) int {
+	if !record.Success {
+		if code := statusForErrorCode(record.ErrorCode); code != http.StatusOK {
+			return code
+		}
+		return http.StatusBadGateway
+	}
+	return http.StatusOK
+}
+
+// stopLogHandler 전체 정지 감사 기록 조회 (최신순)
+func (s *apiServer) stopLogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.allStop.Records())
+}
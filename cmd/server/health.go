@@ -0,0 +1,294 @@
+// ============================================================================
+// cmd/server/health.go - 상태 확인 및 디버그 정보 (/healthz, /readyz, /api/debug)
+// ============================================================================
+// - /healthz: 프로세스가 요청을 처리할 수 있으면 항상 200 (liveness)
+// - /readyz: health.ready_max_age_sec 안에 성공한 상태 조회가 있으면 200, 아니면 503
+// - /api/debug: 빌드 정보, 설정값(비밀 값 가림), 이름 있는 상태 확인 목록
+//
+// 빌드 시각과 커밋은 -ldflags로 주입합니다. 주입하지 않으면 go build가
+// 기록한 VCS 정보를 사용합니다.
+//
+//	go build -ldflags "-X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ) -X main.gitCommit=$(git rev-parse --short HEAD)" ./cmd/server
+// ============================================================================
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/config"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+	"github.com/nir414/go-virtual-pendant/internal/web"
+)
+
+// 빌드 정보 (-ldflags "-X main.buildTime=... -X main.gitCommit=...")
+var (
+	buildTime string
+	gitCommit string
+)
+
+// 상태 확인 결과 (HealthCheck.Status)
+const (
+	HEALTH_OK      = "ok"
+	HEALTH_WARNING = "warning"
+	HEALTH_ERROR   = "error"
+
+	REDACTED = "[REDACTED]"
+)
+
+// secretKeyWords 이 단어가 들어간 설정 키는 값을 가림
+var secretKeyWords = []string{"password", "secret", "token", "apikey", "api_key", "credential"}
+
+// healthServer 상태 확인 엔드포인트
+type healthServer struct {
+	api         *apiServer
+	cfg         *types.AppConfig
+	readyMaxAge time.Duration
+}
+
+// newHealthServer 상태 확인 엔드포인트 생성
+func newHealthServer(api *apiServer, cfg *types.AppConfig) *healthServer {
+	return &healthServer{api: api, cfg: cfg, readyMaxAge: config.ReadyMaxAge(cfg)}
+}
+
+// healthzHandler liveness - 항상 200
+func (h *healthServer) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+	writeHealth(w, http.StatusOK, types.HealthResponse{Status: HEALTH_OK})
+}
+
+// readyzHandler readiness - 최근 상태 조회가 성공했으면 200, 아니면 503
+func (h *healthServer) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	poller := h.checkPoller()
+	resp := types.HealthResponse{Status: "ready", Checks: []types.HealthCheck{h.checkController(), poller}}
+	status := http.StatusOK
+	if poller.Status != HEALTH_OK {
+		resp.Status, status = "not_ready", http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, resp)
+}
+
+// debugHandler 디버그 정보 (GET /api/debug)
+func (h *healthServer) debugHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	built, commit := buildInfo()
+	info := types.DebugInfo{
+		GoVersion:    runtime.Version(),
+		BuildTime:    built,
+		GitCommit:    commit,
+		Platform:     h.cfg.Server.Platform,
+		Environment:  h.cfg.Server.Environment,
+		ConfigValues: redactedConfig(h.cfg),
+		HealthChecks: []types.HealthCheck{
+			h.checkController(),
+			h.checkPoller(),
+			h.checkPayload(),
+			h.checkFreshness(),
+			checkResult("templates", web.CheckTemplates(), "index.html 템플릿 파싱 성공"),
+			checkResult("static_assets", web.CheckStaticAssets(), "필수 정적 파일 확인: "+strings.Join(web.REQUIRED_STATIC_FILES, ", ")),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// ============================================================================
+// 상태 확인 (Checks)
+// ============================================================================
+
+// checkController 컨트롤러 연결 상태 (connected=ok, degraded=warning, 그 외 error)
+func (h *healthServer) checkController() types.HealthCheck {
+	conn := h.api.ctrl.ConnectionStatus()
+	status := HEALTH_ERROR
+	switch robot.ConnectionState(conn.State) {
+	case robot.ConnStateConnected:
+		status = HEALTH_OK
+	case robot.ConnStateDegraded:
+		status = HEALTH_WARNING
+	}
+
+	msg := conn.State
+	if conn.LastErrorMessage != "" {
+		msg += ": " + conn.LastErrorMessage
+	}
+	return newHealthCheck("controller", status, msg)
+}
+
+// checkPoller 상태 폴러 - readyMaxAge 안에 성공한 조회가 있고 마지막 조회가 성공했는지
+func (h *healthServer) checkPoller() types.HealthCheck {
+	snap := h.api.state.Latest()
+	switch {
+	case snap.At.IsZero():
+		return newHealthCheck("poller", HEALTH_ERROR, "아직 성공한 상태 조회가 없습니다")
+	case snap.Err != nil:
+		return newHealthCheck("poller", HEALTH_ERROR, fmt.Sprintf("마지막 조회 실패: %v (마지막 성공 %v 전)", snap.Err, snap.Age().Round(time.Millisecond)))
+	case snap.Age() > h.readyMaxAge:
+		return newHealthCheck("poller", HEALTH_ERROR, fmt.Sprintf("마지막 성공 %v 전 (기준 %v)", snap.Age().Round(time.Millisecond), h.readyMaxAge))
+	}
+	return newHealthCheck("poller", HEALTH_OK, fmt.Sprintf("마지막 성공 %v 전 (seq %d, 기준 %v)", snap.Age().Round(time.Millisecond), snap.Seq, h.readyMaxAge))
+}
+
+// checkPayload 마지막 상태 응답 해석 결과 (필드 오류가 있으면 warning - 레이아웃이 펌웨어와 맞지 않을 수 있음)
+func (h *healthServer) checkPayload() types.HealthCheck {
+	snap := h.api.state.Latest()
+	if snap.State == nil {
+		return newHealthCheck("payload", HEALTH_WARNING, "아직 해석한 상태 응답이 없습니다")
+	}
+	meta := snap.State.Meta
+	if len(meta.ParseErrors) > 0 {
+		e := meta.ParseErrors[0]
+		return newHealthCheck("payload", HEALTH_WARNING, fmt.Sprintf("레이아웃 %s: 필드 오류 %d개 (첫 오류 %s(#%d)=%q)", meta.Layout, len(meta.ParseErrors), e.Field, e.Index, e.Raw))
+	}
+	return newHealthCheck("payload", HEALTH_OK, fmt.Sprintf("레이아웃 %s: 필드 오류 없음, 알 수 없는 필드 %d개", meta.Layout, len(meta.UnknownFields)))
+}
+
+// checkFreshness 상태 데이터 감시 결과 (stale이면 warning)
+func (h *healthServer) checkFreshness() types.HealthCheck {
+	if !h.cfg.Watchdog.Enabled {
+		return newHealthCheck("state_freshness", HEALTH_OK, "상태 데이터 감시 꺼짐")
+	}
+	snap := h.api.state.Latest()
+	if snap.State == nil {
+		return newHealthCheck("state_freshness", HEALTH_WARNING, "아직 확인한 상태가 없습니다")
+	}
+	if snap.State.Meta.Stale {
+		return newHealthCheck("state_freshness", HEALTH_WARNING, "stale: "+snap.State.Meta.StaleReason)
+	}
+	return newHealthCheck("state_freshness", HEALTH_OK, "응답 "+snap.State.Meta.Fingerprint)
+}
+
+// checkResult 오류 여부로 상태 확인 결과 생성
+func checkResult(name string, err error, okMessage string) types.HealthCheck {
+	if err != nil {
+		return newHealthCheck(name, HEALTH_ERROR, err.Error())
+	}
+	return newHealthCheck(name, HEALTH_OK, okMessage)
+}
+
+// newHealthCheck 현재 시각으로 상태 확인 결과 생성
+func newHealthCheck(name, status, message string) types.HealthCheck {
+	return types.HealthCheck{Name: name, Status: status, Message: message, Timestamp: time.Now().Format(time.RFC3339Nano)}
+}
+
+// writeHealth 상태 확인 응답 JSON 기록
+func writeHealth(w http.ResponseWriter, status int, resp types.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ============================================================================
+// 빌드 정보와 설정값 (Build Info & Config)
+// ============================================================================
+
+// buildInfo -ldflags로 주입한 빌드 시각/커밋 (없으면 go build의 VCS 정보, 그래도 없으면 "unknown")
+func buildInfo() (built string, commit string) {
+	built, commit = buildTime, gitCommit
+	if info, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.time":
+				if built == "" {
+					built = s.Value
+				}
+			case "vcs.revision":
+				if commit == "" {
+					commit = s.Value
+				}
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if modified && gitCommit == "" && commit != "" {
+			commit += "-dirty"
+		}
+	}
+	if built == "" {
+		built = "unknown"
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	return built, commit
+}
+
+// redactedConfig 적용된 설정을 "section.key" → 값으로 펼침 (비밀 값은 가림)
+func redactedConfig(cfg *types.AppConfig) map[string]string {
+	data, _ := json.Marshal(cfg)
+	var tree map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.Decode(&tree)
+
+	values := make(map[string]string)
+	flattenConfig("", tree, values)
+	return values
+}
+
+// flattenConfig 중첩된 설정 값을 점으로 이은 키로 펼침
+func flattenConfig(prefix string, v interface{}, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenConfig(key, v[k], out)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flattenConfig(fmt.Sprintf("%s[%d]", prefix, i), item, out)
+		}
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = redactValue(prefix, fmt.Sprint(v))
+	}
+}
+
+// redactValue 비밀 키는 값 전체를, URL은 사용자 정보의 비밀번호를 가림
+func redactValue(key, value string) string {
+	lower := strings.ToLower(key)
+	for _, word := range secretKeyWords {
+		if strings.Contains(lower, word) && value != "" {
+			return REDACTED
+		}
+	}
+	if strings.Contains(value, "@") && strings.Contains(value, "://") {
+		if u, err := url.Parse(value); err == nil && u.User != nil {
+			return u.Redacted()
+		}
+	}
+	return value
+}
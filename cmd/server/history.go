@@ -0,0 +1,146 @@
+// ============================================================================
+// cmd/server/history.go - 위치 이력 조회 API (/api/history)
+// ============================================================================
+// GET /api/history?from=&to=&axes=&downsample=&format=
+//
+// - from, to: RFC 3339 시각, 유닉스 밀리초, 또는 지금 기준 음수 기간 ("-60s")
+// - axes: 쉼표로 구분한 모델 축 별칭 (예: "joint1,j2,x" - 없으면 모든 축)
+// - downsample: 구간 길이 ("1s", "500ms" 또는 밀리초) - 구간별 min/max/avg
+// - format: "json"(기본) 또는 "csv" (Accept: text/csv도 가능)
+// ============================================================================
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/config"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// historyHandler 위치 이력 조회
+func (s *apiServer) historyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := parseHistoryQuery(r, time.Now())
+	if err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.history.Query(q)
+	if errors.Is(err, robot.ErrInvalidHistoryQuery) {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		format = "csv"
+	}
+	switch format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="history.csv"`)
+		writeHistoryCSV(w, resp)
+	default:
+		http.Error(w, MSG_BAD_REQUEST+": format은 json 또는 csv여야 합니다", http.StatusBadRequest)
+	}
+}
+
+// parseHistoryQuery 쿼리 문자열을 이력 조회 조건으로 변환
+func parseHistoryQuery(r *http.Request, now time.Time) (robot.HistoryQuery, error) {
+	var q robot.HistoryQuery
+	values := r.URL.Query()
+
+	var err error
+	if q.From, err = parseHistoryTime(values.Get("from"), now); err != nil {
+		return q, errors.New("from " + err.Error())
+	}
+	if q.To, err = parseHistoryTime(values.Get("to"), now); err != nil {
+		return q, errors.New("to " + err.Error())
+	}
+	if v := values.Get("axes"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				q.Axes = append(q.Axes, name)
+			}
+		}
+	}
+	if v := values.Get("downsample"); v != "" {
+		if q.Bucket, err = config.ParseMillis(v); err != nil {
+			return q, errors.New("downsample " + err.Error())
+		}
+		if q.Bucket <= 0 {
+			return q, errors.New("downsample은 0보다 커야 합니다")
+		}
+	}
+	return q, nil
+}
+
+// parseHistoryTime RFC 3339 시각, 유닉스 밀리초, 또는 now 기준 음수 기간 ("-90s") 해석
+func parseHistoryTime(v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasPrefix(v, "-") {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+// writeHistoryCSV 이력을 CSV로 기록
+// 원본: time,seq,<축>...  구간: time,count,<축>_min,<축>_max,<축>_avg...
+func writeHistoryCSV(w http.ResponseWriter, resp types.HistoryResponse) {
+	cw := csv.NewWriter(w)
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	if resp.DownsampleMs == 0 {
+		cw.Write(append([]string{"time", "seq"}, resp.Axes...))
+		for _, s := range resp.Samples {
+			row := []string{s.Time, strconv.FormatUint(s.Seq, 10)}
+			for _, v := range s.Values {
+				row = append(row, format(v))
+			}
+			cw.Write(row)
+		}
+	} else {
+		header := []string{"time", "count"}
+		for _, axis := range resp.Axes {
+			header = append(header, axis+"_min", axis+"_max", axis+"_avg")
+		}
+		cw.Write(header)
+		for _, b := range resp.Buckets {
+			row := []string{b.Time, strconv.Itoa(b.Count)}
+			for i := range resp.Axes {
+				row = append(row, format(b.Min[i]), format(b.Max[i]), format(b.Avg[i]))
+			}
+			cw.Write(row)
+		}
+	}
+	cw.Flush()
+}
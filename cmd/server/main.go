@@ -8,17 +8,21 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 
+	"github.com/nir414/go-virtual-pendant/internal/clientlog"
+	"github.com/nir414/go-virtual-pendant/internal/config"
+	"github.com/nir414/go-virtual-pendant/internal/logging"
 	"github.com/nir414/go-virtual-pendant/internal/robot"
-	"github.com/nir414/go-virtual-pendant/internal/types"
+	"github.com/nir414/go-virtual-pendant/internal/simulator"
 	"github.com/nir414/go-virtual-pendant/internal/web"
 )
 
@@ -27,17 +31,55 @@ import (
 // ============================================================================
 
 const (
-	// 서버 설정
-	DEFAULT_PORT  = "8082"
+	// 서버 설정 (포트 등 실행 설정은 internal/config 참고)
 	DEFAULT_HOST  = "localhost"
 	API_BASE_PATH = "/api"
 	STATIC_PATH   = "/static/"
 
 	// API 엔드포인트
-	ENDPOINT_JOG       = "/api/jog"
-	ENDPOINT_JOG_STATE = "/api/jog/state"
-	ENDPOINT_JOG_MODE  = "/api/jog/mode"
-	ENDPOINT_JOG_AXIS  = "/api/jog/axis"
+	ENDPOINT_JOG        = "/api/jog"
+	ENDPOINT_JOG_STATE  = "/api/jog/state"
+	ENDPOINT_JOG_MODE   = "/api/jog/mode"
+	ENDPOINT_JOG_AXIS   = "/api/jog/axis"
+	ENDPOINT_CONNECTION = "/api/connection"
+	ENDPOINT_MODEL      = "/api/model"
+
+	// 연속 JOG 세션 (서버 측 반복 전송 + 데드맨 하트비트)
+	ENDPOINT_JOG_SESSIONS          = "/api/jog/sessions"
+	ENDPOINT_JOG_SESSION_START     = "/api/jog/session/start"
+	ENDPOINT_JOG_SESSION_HEARTBEAT = "/api/jog/session/heartbeat"
+	ENDPOINT_JOG_SESSION_STOP      = "/api/jog/session/stop"
+
+	// 전체 정지
+	ENDPOINT_STOP     = "/api/stop"
+	ENDPOINT_STOP_LOG = "/api/stop/log"
+
+	// 이벤트 스트림 (SSE)
+	ENDPOINT_EVENTS = "/api/events"
+
+	// 위치 이력
+	ENDPOINT_HISTORY = "/api/history"
+
+	// 궤적 기록
+	ENDPOINT_RECORDINGS         = "/api/recordings"
+	ENDPOINT_RECORDING_START    = "/api/recordings/start"
+	ENDPOINT_RECORDING_STOP     = "/api/recordings/stop"
+	ENDPOINT_RECORDING_DOWNLOAD = "/api/recordings/download"
+
+	// 상태 확인 및 디버그 정보
+	ENDPOINT_HEALTHZ = "/healthz"
+	ENDPOINT_READYZ  = "/readyz"
+	ENDPOINT_DEBUG   = "/api/debug"
+
+	// 브라우저 클라이언트 로그 (수신, 조회)
+	ENDPOINT_CLIENT_LOG  = "/client-log"
+	ENDPOINT_CLIENT_LOGS = "/api/client-logs"
+
+	// 관리 (실행 중 로그 레벨 변경)
+	ENDPOINT_ADMIN_LOG_LEVEL = "/api/admin/loglevel"
+
+	// 요청 추적 ID 헤더 (본문 meta.trace_id가 없을 때)
+	HEADER_TRACE_ID = "X-Trace-ID"
 
 	// 메시지
 	MSG_METHOD_NOT_ALLOWED = "Method Not Allowed"
@@ -47,119 +89,6 @@ const (
 	MSG_SET_AXIS_FAILED    = "Failed to set axis"
 )
 
-// ============================================================================
-// API 핸들러 함수들 (API Handlers)
-// ============================================================================
-
-// jogHandler JOG 명령 요청 처리
-func jogHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var cmd types.JogCommand
-	err := json.NewDecoder(r.Body).Decode(&cmd)
-	if err != nil {
-		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// 로봇에 JOG 명령 전송
-	response, err := robot.SendJogCommand(cmd)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if response.Success {
-			w.WriteHeader(http.StatusOK)
-		} else {
-			w.WriteHeader(http.StatusBadGateway)
-		}
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// jogStateHandler 로봇 상태 조회 요청 처리
-func jogStateHandler(w http.ResponseWriter, r *http.Request) {
-	data, err := robot.GetRobotData()
-	if err != nil {
-		http.Error(w, MSG_FETCH_STATE_FAILED, http.StatusBadGateway)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
-}
-
-// setJogModeHandler JOG 모드 변경 요청 처리
-func setJogModeHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req types.SetJogModeRequest
-
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	response, err := robot.SetRobotJogMode(req.Mode)
-	if err != nil {
-		http.Error(w, "Failed to set jog mode", http.StatusBadGateway)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// setAxisHandler 축 선택 요청 처리
-func setAxisHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req types.SetAxisRequest
-
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	response, err := robot.SetRobotAxis(req.Axis, req.Robot)
-	if err != nil {
-		http.Error(w, "Failed to set axis", http.StatusBadGateway)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// clientLogHandler 브라우저 클라이언트 로그 수신 및 터미널 출력
-func clientLogHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
-		return
-	}
-	var msg interface{}
-	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
-		fmt.Println(">>> [CLIENT LOG] decode error:", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-	fmt.Println(">>> [CLIENT LOG]", msg)
-	w.WriteHeader(http.StatusNoContent)
-}
-
 // ============================================================================
 // 서버 관리 함수들 (Server Management)
 // ============================================================================
@@ -173,7 +102,7 @@ func checkPortConflict(port string) {
 		// Windows용 명령어
 		fmt.Printf("   1️⃣  포트 사용 프로세스 확인: netstat -ano | findstr :%s\n", port)
 		fmt.Println("   2️⃣  프로세스 종료: taskkill /PID <PID번호> /F")
-		fmt.Println("   3️⃣  또는 다른 포트 사용을 원한다면 코드에서 포트 번호 변경")
+		fmt.Println("   3️⃣  또는 -port 플래그나 VP_PORT 환경변수로 다른 포트 사용")
 
 		// 실제로 포트 사용 프로세스 찾기 시도
 		fmt.Printf("\n🔎 포트 %s 사용 중인 프로세스 자동 검색:\n", port)
@@ -202,7 +131,7 @@ func checkPortConflict(port string) {
 		// Linux/Mac용 명령어
 		fmt.Printf("   1️⃣  포트 사용 프로세스 확인: lsof -i :%s\n", port)
 		fmt.Println("   2️⃣  프로세스 종료: kill -9 <PID번호>")
-		fmt.Println("   3️⃣  또는 다른 포트 사용을 원한다면 코드에서 포트 번호 변경")
+		fmt.Println("   3️⃣  또는 -port 플래그나 VP_PORT 환경변수로 다른 포트 사용")
 
 		// 실제로 포트 사용 프로세스 찾기 시도
 		fmt.Printf("\n🔎 포트 %s 사용 중인 프로세스 자동 검색:\n", port)
@@ -222,8 +151,8 @@ func checkPortConflict(port string) {
 }
 
 // startServerWithErrorHandling 서버 시작 및 에러 처리
-func startServerWithErrorHandling(port string) {
-	err := http.ListenAndServe(":"+port, nil)
+func startServerWithErrorHandling(host, port string, handler http.Handler) {
+	err := http.ListenAndServe(net.JoinHostPort(host, port), handler)
 	if err != nil {
 		if strings.Contains(err.Error(), "bind") && strings.Contains(err.Error(), "address already in use") ||
 			strings.Contains(err.Error(), "Only one usage of each socket address") {
@@ -232,38 +161,206 @@ func startServerWithErrorHandling(port string) {
 			os.Exit(1)
 		} else {
 			// 기타 서버 오류
-			log.Fatalf("❌ 서버 시작 실패: %v", err)
+			logging.Info("❌ 서버 시작 실패: %v", err)
+			os.Exit(1)
 		}
 	}
 }
 
+// startSimulator 내장 가상 컨트롤러를 루프백 포트에서 실행하고 주소 반환
+// 가상 컨트롤러는 로봇 모델의 조인트 수를 축 개수로 보고합니다.
+func startSimulator(model *robot.Model) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		logging.Info("❌ 시뮬레이터 시작 실패: %v", err)
+		os.Exit(1)
+	}
+
+	sim := simulator.New(simulator.Options{AxisCount: len(model.Info().Joints)})
+	go func() {
+		if err := http.Serve(ln, sim.Handler()); err != nil {
+			logging.Info("❌ 시뮬레이터 종료: %v", err)
+		}
+	}()
+
+	return "http://" + ln.Addr().String()
+}
+
 // ============================================================================
 // 메인 함수 (Main Function)
 // ============================================================================
 
-// main 서버 진입점 - 포트 8082에서 서버 실행
+// main 서버 진입점 - 설정된 포트(기본 8082)에서 서버 실행
 func main() {
+	// 설정 로드 (기본값 → 설정 파일 → 환경변수 → 플래그)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "❌ 설정 오류:\n%v\n", err)
+		os.Exit(2)
+	}
+	logging.SetLevel(cfg.Server.LogLevel)
+	logging.SetFormat(cfg.Server.LogFormat) // Validate에서 확인한 값
+	web.Configure(cfg.Server.StaticPath, cfg.Server.TemplatePath)
+
+	// 로봇 모델 로드 (축, 모드, PID 정의)
+	model, err := robot.LoadModel(cfg.Controller.Model)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 설정 오류:\n%v\n", err)
+		os.Exit(2)
+	}
+	// 상태 응답 레이아웃 로드 (펌웨어별 jogrefresh.asp 필드 위치)
+	layout, err := robot.LoadPayloadLayout(cfg.Controller.PayloadLayout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 설정 오류:\n%v\n", err)
+		os.Exit(2)
+	}
+
+	// 로봇 컨트롤러 주소 결정 (시뮬레이터 모드면 내장 시뮬레이터)
+	address := cfg.Controller.Address
+	if cfg.Controller.Simulate {
+		address = startSimulator(model)
+		fmt.Printf("🧪 시뮬레이터 모드: 가상 컨트롤러 %s\n", address)
+	}
+
+	// 로봇 컨트롤러 생성 (기본 HTTP 클라이언트)
+	ctrl := robot.NewHTTPController(address, nil, robot.ControllerOptions{
+		Timeout:        config.Timeout(cfg),
+		FollowRedirect: cfg.Controller.FollowRedirect,
+		Model:          model,
+		Layout:         layout,
+		Connection: robot.ConnectionOptions{
+			FailuresToDegraded:   cfg.Controller.FailuresToDegraded,
+			FailuresToDisconnect: cfg.Controller.FailuresToDisconnect,
+			SuccessesToRecover:   cfg.Controller.SuccessesToRecover,
+			BackoffMin:           config.Millis(cfg.Controller.BackoffMinMs),
+			BackoffMax:           config.Millis(cfg.Controller.BackoffMaxMs),
+		},
+	})
+	// 컨트롤러로 보낸 요청마다 지연과 결과를 메트릭으로 기록
+	serverMetrics := newServerMetrics()
+	observed := robot.NewObservedController(ctrl, serverMetrics.observeCommand)
+	// 소프트 리밋 검사 (큐에서 꺼낸 시점의 위치로 검사)
+	// 위치는 상태 폴링 결과를 그대로 쓰고, 폴링 주기보다 오래되었을 때만 다시 조회
+	limits := cfg.Limits
+	limits.MaxStateAgeMs = int(config.LimitStateMaxAge(cfg).Milliseconds())
+	limited, err := robot.NewLimitedController(observed, model, limits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 설정 오류:\n%v\n", err)
+		os.Exit(2)
+	}
+
+	// 모든 쓰기 명령은 하나의 큐로 직렬화 (중단 명령 우선)
+	queue := robot.NewQueuedController(limited, robot.QueueOptions{})
+	// 상태 조회는 하나의 폴러가 담당하고 API와 모니터는 캐시를 공유
+	pollInterval := config.PollInterval(cfg)
+	state := robot.NewStateBroker(queue, pollInterval)
+	state.SetKinematics(robot.NewKinematicsEstimator(model, config.Kinematics(cfg)))
+	// 상태 변화와 API 명령을 이벤트로 게시 (SSE /api/events)
+	events := robot.NewEventBus(robot.DEFAULT_EVENT_BUFFER_SIZE)
+	// 명령을 보내면 폴링을 빠르게 (연속 JOG 세션은 반복 전송마다)
+	var commands robot.Controller = robot.NewPollBoostController(queue, state)
+	// 상태 데이터 감시 - JOG 중 멈춘 응답과 늦은 조회 (block_jog면 stale인 동안 JOG 거부)
+	if cfg.Watchdog.Enabled {
+		watchdog := robot.NewStaleWatchdog(cfg.Watchdog, events)
+		state.SetWatchdog(watchdog)
+		commands = robot.NewStaleGuardController(commands, watchdog)
+	}
+	sessions := robot.NewJogSessionManager(commands, robot.JogSessionOptions{
+		RepeatInterval:   config.Millis(cfg.Jog.RepeatIntervalMs),
+		HeartbeatTimeout: config.Millis(cfg.Jog.HeartbeatTimeoutMs),
+	})
+	history := robot.NewHistory(model, cfg.History)
+	recorder := robot.NewRecorder(model, events, cfg.Recording)
+	clientLogs := clientlog.New(cfg.ClientLog)
+	api := newAPIServer(robot.NewEventController(commands, events), state, model, sessions, robot.NewAllStop(queue, sessions), events, history, recorder, clientLogs)
+	// 적응형 폴링 - 궤적 기록 중에는 보는 클라이언트가 없어도 기본 주기 유지
+	pollPolicy, adaptive := config.PollPolicy(cfg)
+	if adaptive {
+		pollPolicy.Watching = func() bool { return recorder.ActiveCount() > 0 }
+		state.SetPollPolicy(pollPolicy)
+	}
+
 	// 정적 파일 서빙 (CSS, JS)
 	http.HandleFunc(STATIC_PATH, web.StaticFileHandler)
 
 	// API 엔드포인트 등록
-	http.HandleFunc(ENDPOINT_JOG, jogHandler)
-	http.HandleFunc(ENDPOINT_JOG_STATE, jogStateHandler)
-	http.HandleFunc(ENDPOINT_JOG_MODE, setJogModeHandler)
-	http.HandleFunc(ENDPOINT_JOG_AXIS, setAxisHandler)
-	http.HandleFunc("/client-log", clientLogHandler)
+	api.registerRoutes(http.DefaultServeMux)
+
+	// WebSocket 상태 스트림 + 명령 채널 (server.enable_wss, websocket.enable)
+	var ws *wsServer
+	wsEnabled := cfg.Server.EnableWSS && cfg.WebSocket.Enable
+	if wsEnabled {
+		ws = newWSServer(api, cfg.WebSocket)
+		http.HandleFunc(cfg.WebSocket.Endpoint, ws.handler)
+	}
+
+	// 상태 확인 (liveness/readiness) 및 디버그 정보
+	health := newHealthServer(api, cfg)
+	http.HandleFunc(ENDPOINT_HEALTHZ, health.healthzHandler)
+	http.HandleFunc(ENDPOINT_READYZ, health.readyzHandler)
+	http.HandleFunc(ENDPOINT_DEBUG, health.debugHandler)
+
+	// Prometheus 메트릭 (metrics.enable)
+	if cfg.Metrics.Enable {
+		serverMetrics.registerGauges(api, ws)
+		http.Handle(cfg.Metrics.Endpoint, serverMetrics.registry.Handler())
+	}
 
 	// 웹 인터페이스 (템플릿 사용)
 	http.HandleFunc("/", web.InterfaceHandler)
 
 	// 서버 시작 메시지
-	fmt.Printf("🚀 Virtual Pendant API running on http://%s:%s\n", DEFAULT_HOST, DEFAULT_PORT)
-	fmt.Printf("🌐 웹 인터페이스: http://%s:%s\n", DEFAULT_HOST, DEFAULT_PORT)
-	fmt.Println("📍 로봇 위치 모니터링 시작 (1초마다 간격)")
-
-	// 로봇 위치 모니터링 고루틴 시작
-	go robot.MonitorRobotPosition()
+	displayHost := cfg.Server.Host
+	if displayHost == "" {
+		displayHost = DEFAULT_HOST
+	}
+	fmt.Printf("🚀 Virtual Pendant API running on http://%s:%s\n", displayHost, cfg.Server.Port)
+	fmt.Printf("🌐 웹 인터페이스: http://%s:%s\n", displayHost, cfg.Server.Port)
+	fmt.Printf("🤖 로봇 컨트롤러: %s (타임아웃 %v)\n", ctrl.BaseURL(), config.Timeout(cfg))
+	fmt.Printf("🦾 로봇 모델: %s (조인트 %d축, 카르테시안 %d축)\n", model.Name(), len(model.Info().Joints), len(model.Info().Cartesian))
+	fmt.Printf("📦 상태 응답 레이아웃: %s\n", layout.Name())
+	if adaptive {
+		fmt.Printf("📍 로봇 상태 폴링 시작 (적응형: 움직임/명령 중 %v, 기본 %v, 유휴 최대 %v - API와 모니터가 공유)\n", pollPolicy.Min, pollInterval, pollPolicy.Max)
+	} else {
+		fmt.Printf("📍 로봇 상태 폴링 시작 (%v 간격, API와 모니터가 공유)\n", pollInterval)
+	}
+	if cfg.Limits.Enabled {
+		joints, cartesian := limited.Limits()
+		fmt.Printf("🚧 소프트 리밋 사용: 조인트 %d축, 카르테시안 %d축 (최대 스텝 %.1f)\n", len(joints), len(cartesian), cfg.Limits.MaxStep)
+	}
+	if wsEnabled {
+		fmt.Printf("🔌 WebSocket: ws://%s:%s%s (최대 %d개 연결, 하트비트 %d초)\n", displayHost, cfg.Server.Port, cfg.WebSocket.Endpoint, cfg.WebSocket.MaxConnections, cfg.WebSocket.HeartbeatInterval)
+	}
+	if cfg.Metrics.Enable {
+		fmt.Printf("📊 메트릭: http://%s:%s%s (Prometheus 텍스트 형식)\n", displayHost, cfg.Server.Port, cfg.Metrics.Endpoint)
+	}
+	fmt.Printf("📝 로그: %s, %s 형식 (실행 중 변경: PUT %s)\n", cfg.Server.LogLevel, cfg.Server.LogFormat, ENDPOINT_ADMIN_LOG_LEVEL)
+	fmt.Printf("🌐 클라이언트 로그: %s (%dMB × %d개 회전, 조회 %s)\n", clientLogs.Path(), cfg.ClientLog.MaxSizeMB, cfg.ClientLog.MaxFiles, ENDPOINT_CLIENT_LOGS)
+	fmt.Printf("🩺 상태 확인: %s, %s (최근 %v 안에 조회 성공), 디버그 정보 %s\n", ENDPOINT_HEALTHZ, ENDPOINT_READYZ, config.ReadyMaxAge(cfg), ENDPOINT_DEBUG)
+	fmt.Printf("🕘 위치 이력: 최근 %d초 (최대 %d개 샘플)\n", cfg.History.RetentionSec, cfg.History.MaxSamples)
+	fmt.Printf("⏺️  궤적 기록: %s (동시 최대 %d개)\n", recorder.Dir(), cfg.Recording.MaxActive)
+	if cfg.Watchdog.Enabled {
+		block := ""
+		if cfg.Watchdog.BlockJog {
+			block = ", stale인 동안 JOG 차단"
+		}
+		fmt.Printf("🐕 상태 데이터 감시: JOG 뒤 %dms 동안 응답이 같거나 조회가 %dms 넘게 늦으면 stale%s\n", cfg.Watchdog.FrozenMs, cfg.Watchdog.LatePollMs, block)
+	}
+	fmt.Printf("🛑 연속 JOG 데드맨: 하트비트 %dms 없으면 자동 중단 (반복 %dms)\n", cfg.Jog.HeartbeatTimeoutMs, cfg.Jog.RepeatIntervalMs)
+
+	// 상태 폴러와 상태 이벤트 고루틴 시작
+	go state.Run()
+	// 상태 이벤트 감지기 - 콘솔 출력과 SSE 이벤트는 감지기 구독자
+	detector := robot.NewEventDetector(model, cfg.Detector)
+	robot.MonitorRobotPosition(detector)
+	go detector.Run(state)
+	go robot.WatchStateEvents(state, detector, events, model)
+	go history.Run(state)
+	go recorder.Run()
 
 	// 서버 시작 - 포트 충돌 시 자동 해결 방법 안내
-	startServerWithErrorHandling(DEFAULT_PORT)
+	startServerWithErrorHandling(cfg.Server.Host, cfg.Server.Port, serverMetrics.instrument(http.DefaultServeMux))
 }
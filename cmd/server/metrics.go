@@ -0,0 +1,249 @@
+// ============================================================================
+// cmd/server/metrics.go - Prometheus 메트릭 (/metrics)
+// ============================================================================
+// 컨트롤러 요청 지연, 상태 조회 성공/실패, 연결 상태, 현재 위치,
+// 엔드포인트별 HTTP 요청 수, 접속 중인 클라이언트 수를 노출합니다.
+//
+// 수집 방식:
+// - 컨트롤러 요청: robot.ObservedController 관측 콜백 (큐 대기 시간 제외)
+// - HTTP 요청: mux를 감싸는 미들웨어 (엔드포인트 = 등록된 경로 패턴)
+// - 게이지: 스크랩할 때 상태 브로커, 컨트롤러, 세션 관리자에서 읽음
+// ============================================================================
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/metrics"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// serverMetrics 서버 메트릭 모음
+type serverMetrics struct {
+	registry        *metrics.Registry
+	commandDuration *metrics.Histogram
+	commands        *metrics.Counter
+	polls           *metrics.Counter
+	httpRequests    *metrics.Counter
+}
+
+// newServerMetrics 요청 기반 메트릭 등록 (게이지는 registerGauges)
+func newServerMetrics() *serverMetrics {
+	r := metrics.NewRegistry()
+	return &serverMetrics{
+		registry: r,
+		commandDuration: r.Histogram("vp_controller_request_duration_seconds",
+			"컨트롤러 요청 지연 (큐 대기 제외)", metrics.DEFAULT_LATENCY_BUCKETS, "command"),
+		commands: r.Counter("vp_controller_commands_total",
+			"컨트롤러로 보낸 명령 수 (result: ok, rejected, error)", "command", "result"),
+		polls: r.Counter("vp_state_polls_total",
+			"컨트롤러 상태 조회 수 (result: success, failure, skipped)", "result"),
+		httpRequests: r.Counter("vp_http_requests_total",
+			"엔드포인트별 HTTP 요청 수", "endpoint", "method", "code"),
+	}
+}
+
+// observeCommand robot.CommandObserver 구현
+func (m *serverMetrics) observeCommand(command string, elapsed time.Duration, resp *types.JogResponse, err error) {
+	if command == robot.CommandPoll {
+		switch {
+		case errors.Is(err, robot.ErrReconnectBackoff):
+			m.polls.Inc("skipped") // 요청하지 않았으므로 지연도 기록하지 않음
+			return
+		case err != nil:
+			m.polls.Inc("failure")
+		default:
+			m.polls.Inc("success")
+		}
+		m.commandDuration.Observe(elapsed.Seconds(), command)
+		return
+	}
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+	} else if resp != nil && !resp.Success {
+		result = "rejected"
+	}
+	m.commands.Inc(command, result)
+	m.commandDuration.Observe(elapsed.Seconds(), command)
+}
+
+// registerGauges 스크랩할 때 읽는 게이지 등록 (ws는 WebSocket이 비활성화되어 있으면 nil)
+func (m *serverMetrics) registerGauges(api *apiServer, ws *wsServer) {
+	r := m.registry
+
+	r.GaugeFunc("vp_state_last_success_age_seconds",
+		"마지막으로 성공한 상태 조회 이후 지난 시간", nil,
+		func(set func(float64, ...string)) {
+			if snap := api.state.Latest(); !snap.At.IsZero() {
+				set(snap.Age().Seconds())
+			}
+		})
+
+	r.GaugeFunc("vp_state_poll_interval_seconds",
+		"현재 상태 폴링 주기 (mode: fast, watched, idle, fixed - 현재 상태만)", []string{"mode"},
+		func(set func(float64, ...string)) {
+			interval, mode := api.state.EffectiveInterval()
+			set(interval.Seconds(), mode)
+		})
+
+	r.GaugeFunc("vp_state_stale",
+		"마지막 상태를 믿을 수 없음 (reason: frozen, late_poll - stale이면 1)", []string{"reason"},
+		func(set func(float64, ...string)) {
+			if snap := api.state.Latest(); snap.State != nil {
+				for _, reason := range []string{robot.StaleFrozen, robot.StaleLatePoll} {
+					v := 0.0
+					if snap.State.Meta.StaleReason == reason {
+						v = 1
+					}
+					set(v, reason)
+				}
+			}
+		})
+
+	r.GaugeFunc("vp_state_poll_rate_hz",
+		"현재 상태 폴링 빈도 (초당 조회 수)", nil,
+		func(set func(float64, ...string)) {
+			if interval, _ := api.state.EffectiveInterval(); interval > 0 {
+				set(1 / interval.Seconds())
+			}
+		})
+
+	r.GaugeFunc("vp_controller_connection_state",
+		"컨트롤러 연결 상태 (현재 상태만 1)", []string{"state"},
+		func(set func(float64, ...string)) {
+			current := api.ctrl.ConnectionStatus().State
+			for _, state := range []robot.ConnectionState{robot.ConnStateConnecting, robot.ConnStateConnected, robot.ConnStateDegraded, robot.ConnStateDisconnected} {
+				v := 0.0
+				if string(state) == current {
+					v = 1
+				}
+				set(v, string(state))
+			}
+		})
+
+	info := api.model.Info()
+	r.GaugeFunc("vp_robot_joint_position",
+		"현재 조인트 위치 (모델 축 단위)", []string{"axis"},
+		func(set func(float64, ...string)) {
+			if state := api.state.Latest().State; state != nil {
+				for i, axis := range info.Joints {
+					if i < len(state.Joint) {
+						set(state.Joint[i], axis.Aliases[0])
+					}
+				}
+			}
+		})
+	r.GaugeFunc("vp_robot_cartesian_position",
+		"현재 카르테시안 위치 (mm, 회전축은 도)", []string{"axis"},
+		func(set func(float64, ...string)) {
+			if state := api.state.Latest().State; state != nil {
+				for i, axis := range info.Cartesian {
+					if i < len(state.Cartesian) {
+						set(state.Cartesian[i], axis.Aliases[0])
+					}
+				}
+			}
+		})
+
+	r.GaugeFunc("vp_active_clients",
+		"접속 중인 스트리밍 클라이언트 수", []string{"transport"},
+		func(set func(float64, ...string)) {
+			set(float64(atomic.LoadInt32(&api.sseClients)), "sse")
+			if ws != nil {
+				set(float64(atomic.LoadInt32(&ws.active)), "websocket")
+			}
+		})
+	r.GaugeFunc("vp_jog_sessions_active",
+		"진행 중인 연속 JOG 세션 수", nil,
+		func(set func(float64, ...string)) {
+			set(float64(api.sessions.ActiveCount()))
+		})
+}
+
+// ============================================================================
+// HTTP 요청 계측 (Instrumentation)
+// ============================================================================
+
+// instrument mux의 요청을 엔드포인트(등록된 경로 패턴)별로 셉니다.
+func (m *serverMetrics) instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+		rec := &statusRecorder{ResponseWriter: w}
+		mux.ServeHTTP(rec, r)
+		m.httpRequests.Inc(pattern, metricMethod(r.Method), strconv.Itoa(rec.code()))
+	})
+}
+
+// metricMethod 레이블 값이 무한히 늘지 않도록 알려진 메서드만 그대로 사용
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodPatch:
+		return method
+	}
+	return "other"
+}
+
+// statusRecorder 응답 상태 코드 기록 (SSE Flush, WebSocket Hijack 그대로 지원)
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush SSE 스트림용
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack WebSocket 업그레이드용
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("http.Hijacker를 지원하지 않는 ResponseWriter")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap http.ResponseController용
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// code 기록된 상태 코드 (아무것도 쓰지 않았으면 200)
+func (w *statusRecorder) code() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
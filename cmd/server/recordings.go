@@ -0,0 +1,140 @@
+// ============================================================================
+// cmd/server/recordings.go - 궤적 기록 API (/api/recordings)
+// ============================================================================
+// GET    /api/recordings                  - 기록 목록 (진행 중 포함, 최신순)
+// DELETE /api/recordings?name=            - 기록 파일 삭제 (진행 중이면 409)
+// POST   /api/recordings/start            - {"name", "format": "csv"|"jsonl"}
+// POST   /api/recordings/stop             - {"name"}
+// GET    /api/recordings/download?name=   - 기록 파일 내려받기 (진행 중이면 지금까지)
+// ============================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// recordingsHandler 기록 목록 조회와 삭제
+func (s *apiServer) recordingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := s.recorder.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []types.RecordingInfo{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if err := s.recorder.Delete(name); err != nil {
+			writeRecordingResponse(w, recordingStatus(err), types.RecordingResponse{Success: false, Message: err.Error()})
+			return
+		}
+		writeRecordingResponse(w, http.StatusOK, types.RecordingResponse{Success: true, Message: "기록을 삭제했습니다: " + name})
+
+	default:
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+	}
+}
+
+// recordingStartHandler 기록 시작
+func (s *apiServer) recordingStartHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.RecordingStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, err := s.recorder.Start(req.Name, req.Format, clientID(r, req.Meta))
+	if err != nil {
+		writeRecordingResponse(w, recordingStatus(err), types.RecordingResponse{Success: false, Message: err.Error()})
+		return
+	}
+	writeRecordingResponse(w, http.StatusOK, types.RecordingResponse{Success: true, Message: "기록을 시작했습니다", Recording: &info})
+}
+
+// recordingStopHandler 기록 중단
+func (s *apiServer) recordingStopHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.RecordingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, MSG_BAD_REQUEST+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, err := s.recorder.Stop(req.Name)
+	if err != nil {
+		writeRecordingResponse(w, recordingStatus(err), types.RecordingResponse{Success: false, Message: err.Error()})
+		return
+	}
+	writeRecordingResponse(w, http.StatusOK, types.RecordingResponse{Success: true, Message: "기록을 중단했습니다", Recording: &info})
+}
+
+// recordingDownloadHandler 기록 파일 내려받기 (Range 요청 지원)
+func (s *apiServer) recordingDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, MSG_METHOD_NOT_ALLOWED, http.StatusMethodNotAllowed)
+		return
+	}
+
+	file, info, err := s.recorder.Open(r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), recordingStatus(err))
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if info.Format == robot.RecordingJSONL {
+		contentType = "application/x-ndjson"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+info.File+`"`)
+	http.ServeContent(w, r, info.File, stat.ModTime(), file)
+}
+
+// recordingStatus 기록 오류를 HTTP 상태 코드로 변환
+func recordingStatus(err error) int {
+	switch {
+	case errors.Is(err, robot.ErrInvalidRecording):
+		return http.StatusBadRequest
+	case errors.Is(err, robot.ErrRecordingNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, robot.ErrRecordingExists), errors.Is(err, robot.ErrRecordingActive), errors.Is(err, robot.ErrTooManyRecordings):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeRecordingResponse 기록 응답 JSON 기록
+func writeRecordingResponse(w http.ResponseWriter, status int, resp types.RecordingResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,456 @@
+// ============================================================================
+// cmd/server/websocket.go - WebSocket 상태 스트림 + 명령 채널
+// ============================================================================
+// 연속 JOG 중 30ms마다 HTTP 요청을 새로 만드는 대신 하나의 소켓으로
+// 명령을 보내고 상태 변화를 받습니다.
+//
+// 서버 → 클라이언트:
+// - hello: 연결 정보 (소유자 ID, 하트비트 주기, 모델)
+// - state: 전체 상태 (연결 직후, 상태 조회 오류에서 복구한 뒤)
+// - state_delta: 바뀐 항목만 (cartesian, joint, tool, kinematics, status, meta.stale)
+// - state_error: 상태 조회 실패 (복구되면 state로 다시 시작)
+// - result / error: 요청 ID별 응답 (data는 같은 HTTP 엔드포인트 응답 본문)
+//
+// 클라이언트 → 서버: jog, stop, mode, axis, jog_start, jog_heartbeat, jog_stop
+// 연결이 끊기면 이 연결이 시작한 JOG를 자동으로 중단합니다.
+//
+// stop은 앞선 명령을 기다리지 않고 바로 처리하므로, seq 없이 보낸 jog/stop에는
+// 받은 순서대로 연결별 순번을 매겨(ClientSession = 연결 소유자) 명령 큐가
+// stop보다 먼저 받은 jog를 stop 뒤에 전송하지 않도록 합니다.
+// ============================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+	"github.com/nir414/go-virtual-pendant/internal/websocket"
+)
+
+// WebSocket 처리 상수
+const (
+	WS_COMMAND_BUFFER      = 32 // 연결마다 대기할 수 있는 순차 명령 수
+	MSG_WS_TOO_MANY        = "Too many WebSocket connections"
+	MSG_WS_UNKNOWN_MESSAGE = "알 수 없는 메시지 종류"
+)
+
+// wsServer WebSocket 엔드포인트 (연결 수 제한, 연결별 처리)
+type wsServer struct {
+	api       *apiServer
+	cfg       types.WebSocketConfig
+	heartbeat time.Duration
+
+	active int32  // 현재 연결 수 (atomic)
+	nextID uint64 // 연결 번호 (atomic)
+}
+
+// newWSServer WebSocket 엔드포인트 생성
+func newWSServer(api *apiServer, cfg types.WebSocketConfig) *wsServer {
+	return &wsServer{
+		api:       api,
+		cfg:       cfg,
+		heartbeat: time.Duration(cfg.HeartbeatInterval) * time.Second,
+	}
+}
+
+// handler WebSocket 업그레이드 및 연결 처리 (연결이 끊길 때까지 반환하지 않음)
+func (ws *wsServer) handler(w http.ResponseWriter, r *http.Request) {
+	if n := atomic.AddInt32(&ws.active, 1); int(n) > ws.cfg.MaxConnections {
+		atomic.AddInt32(&ws.active, -1)
+		http.Error(w, MSG_WS_TOO_MANY, http.StatusServiceUnavailable)
+		return
+	}
+	defer atomic.AddInt32(&ws.active, -1)
+
+	conn, err := websocket.Upgrade(w, r, websocket.Options{})
+	if err != nil {
+		return // Upgrade가 HTTP 오류 응답을 이미 보냄
+	}
+
+	c := &wsClient{
+		srv:      ws,
+		conn:     conn,
+		owner:    fmt.Sprintf("ws-%d@%s", atomic.AddUint64(&ws.nextID, 1), r.RemoteAddr),
+		commands: make(chan wsCommand, WS_COMMAND_BUFFER),
+		done:     make(chan struct{}),
+	}
+	logging.Info("🔌 WebSocket 연결: %s (%d/%d)", c.owner, atomic.LoadInt32(&ws.active), ws.cfg.MaxConnections)
+
+	c.run()
+
+	logging.Info("🔌 WebSocket 종료: %s", c.owner)
+}
+
+// ============================================================================
+// 연결별 처리 (Per-Connection Client)
+// ============================================================================
+
+// wsClient 하나의 WebSocket 연결
+type wsClient struct {
+	srv      *wsServer
+	conn     *websocket.Conn
+	owner    string // 세션 소유자 및 기본 ClientSession
+	commands chan wsCommand
+	done     chan struct{}
+	lastSeq  uint64 // 마지막으로 매긴 연결별 순번 (readLoop에서만 증가)
+
+	mu      sync.Mutex
+	jogging bool // 마지막 중단 이후 JOG 명령을 보냈는지
+}
+
+// wsCommand 받은 메시지와 받은 순서대로 매긴 연결별 순번
+type wsCommand struct {
+	msg types.WSMessage
+	seq uint64
+}
+
+// run 상태 전송/명령 처리 고루틴을 시작하고 읽기 루프 실행
+func (c *wsClient) run() {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); c.pushLoop() }()
+	go func() { defer wg.Done(); c.commandLoop() }()
+
+	c.readLoop()
+
+	close(c.done)
+	c.conn.Close()
+	wg.Wait()
+	c.stopOnClose()
+}
+
+// readLoop 클라이언트 메시지 수신 (오류 또는 종료까지)
+// 정지/하트비트는 앞선 명령을 기다리지 않도록 바로 처리하고
+// 나머지 명령은 보낸 순서대로 처리합니다.
+func (c *wsClient) readLoop() {
+	c.conn.SetReadTimeout(2 * c.srv.heartbeat)
+	for {
+		op, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if op != websocket.OpText {
+			continue
+		}
+
+		var msg types.WSMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.send(types.WSEvent{Type: "error", Message: MSG_BAD_REQUEST + ": " + err.Error()})
+			continue
+		}
+
+		c.lastSeq++
+		cmd := wsCommand{msg: msg, seq: c.lastSeq}
+
+		switch msg.Type {
+		case "stop", "jog_heartbeat", "jog_stop":
+			go c.handle(cmd)
+		case "jog", "mode", "axis", "jog_start":
+			select {
+			case c.commands <- cmd:
+			default:
+				c.send(types.WSEvent{Type: "error", ID: msg.ID, Message: types.ErrCodeQueueFull})
+			}
+		default:
+			c.send(types.WSEvent{Type: "error", ID: msg.ID, Message: MSG_WS_UNKNOWN_MESSAGE + ": " + msg.Type})
+		}
+	}
+}
+
+// commandLoop 순차 명령 처리
+func (c *wsClient) commandLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case cmd := <-c.commands:
+			c.handle(cmd)
+		}
+	}
+}
+
+// handle 명령 하나를 처리하고 result 또는 error 응답
+func (c *wsClient) handle(cmd wsCommand) {
+	msg := cmd.msg
+	result, err := c.dispatch(msg, cmd.seq)
+	if err != nil {
+		c.send(types.WSEvent{Type: "error", ID: msg.ID, Message: MSG_BAD_REQUEST + ": " + err.Error()})
+		return
+	}
+	c.send(types.WSEvent{Type: "result", ID: msg.ID, Data: result})
+}
+
+// dispatch 메시지 종류별로 HTTP 핸들러와 같은 동작 수행
+// seq는 readLoop가 매긴 연결별 순번 (jog/stop의 순서 보장용)
+func (c *wsClient) dispatch(msg types.WSMessage, seq uint64) (interface{}, error) {
+	api := c.srv.api
+
+	switch msg.Type {
+	case "jog":
+		var cmd types.JogCommand
+		if err := decodeWSData(msg.Data, &cmd); err != nil {
+			return nil, err
+		}
+		c.sequence(&cmd, seq)
+		start := time.Now()
+		resp, _ := api.ctrl.SendJogCommand(cmd)
+		logCommand("jog", cmd.Meta.TraceID, logging.Fields{"axis": cmd.Axis, "dir": cmd.Dir, "mode": cmd.Mode, "transport": "websocket"}, start, resp)
+		c.setJogging(cmd.Dir != "stop")
+		return resp, nil
+
+	case "stop":
+		// 이 연결의 연속 JOG를 끝내고 중단 명령 전송 (큐에서 우선 처리)
+		var cmd types.JogCommand
+		if err := decodeWSData(msg.Data, &cmd); err != nil {
+			return nil, err
+		}
+		c.stopSessions()
+		cmd.Dir = "stop"
+		c.sequence(&cmd, seq)
+		resp, _ := api.ctrl.SendJogCommand(cmd)
+		c.setJogging(false)
+		return resp, nil
+
+	case "mode":
+		var req types.SetJogModeRequest
+		if err := decodeWSData(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, _ := api.ctrl.SetJogMode(req.Mode)
+		logCommand("mode", req.Meta.TraceID, logging.Fields{"mode": req.Mode, "transport": "websocket"}, start, resp)
+		return resp, nil
+
+	case "axis":
+		var req types.SetAxisRequest
+		if err := decodeWSData(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, _ := api.ctrl.SetAxis(req.Axis, req.Robot)
+		logCommand("axis", req.Meta.TraceID, logging.Fields{"axis": req.Axis, "robot": req.Robot, "transport": "websocket"}, start, resp)
+		return resp, nil
+
+	case "jog_start":
+		var req types.JogSessionStartRequest
+		if err := decodeWSData(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		if req.ClientSession == "" {
+			req.ClientSession = c.owner
+		}
+		return api.startJogSession(req.JogCommand, c.owner), nil
+
+	case "jog_heartbeat":
+		var req types.JogSessionRequest
+		if err := decodeWSData(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		return api.heartbeatJogSession(req.SessionID), nil
+
+	case "jog_stop":
+		var req types.JogSessionRequest
+		if err := decodeWSData(msg.Data, &req); err != nil {
+			return nil, err
+		}
+		return api.stopJogSession(req.SessionID), nil
+	}
+	return nil, fmt.Errorf("%s: %s", MSG_WS_UNKNOWN_MESSAGE, msg.Type)
+}
+
+// decodeWSData 요청 본문 디코딩 (본문이 없으면 빈 요청)
+func decodeWSData(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// sequence jog/stop 명령의 순서 보장 필드 설정
+// 클라이언트가 seq를 보냈으면 그대로 두고, 없으면 연결별 순번과 연결 소유자를 사용합니다.
+func (c *wsClient) sequence(cmd *types.JogCommand, seq uint64) {
+	if cmd.Seq == 0 {
+		cmd.Seq = seq
+		cmd.ClientSession = c.owner
+		return
+	}
+	if cmd.ClientSession == "" {
+		cmd.ClientSession = c.owner
+	}
+}
+
+// setJogging 마지막 중단 이후 JOG 명령 여부 기록
+func (c *wsClient) setJogging(jogging bool) {
+	c.mu.Lock()
+	c.jogging = jogging
+	c.mu.Unlock()
+}
+
+// stopSessions 이 연결이 시작한 연속 JOG 세션 중단 (중단 사유: stopped)
+func (c *wsClient) stopSessions() int {
+	api := c.srv.api
+	stopped := 0
+	for _, info := range api.sessions.Active() {
+		if info.Owner == c.owner {
+			if _, err := api.sessions.Stop(info.SessionID); err == nil {
+				stopped++
+			}
+		}
+	}
+	return stopped
+}
+
+// stopOnClose 연결 종료 시 이 연결의 JOG 중단
+// 세션은 세션마다 중단 명령을 보내고, 세션 없이 보낸 JOG는 중단 명령을 따로 보냅니다.
+func (c *wsClient) stopOnClose() {
+	api := c.srv.api
+	stopped := api.sessions.StopOwner(c.owner)
+
+	c.mu.Lock()
+	jogging := c.jogging
+	c.mu.Unlock()
+
+	if jogging {
+		api.ctrl.SendJogCommand(types.JogCommand{Dir: "stop", ClientSession: c.owner, Seq: c.lastSeq + 1})
+	}
+	if stopped > 0 || jogging {
+		logging.Info("🛑 WebSocket 연결 종료로 JOG 중단: %s (세션 %d개)", c.owner, stopped)
+	}
+}
+
+// send 이벤트 전송 (실패하면 연결을 닫아 읽기 루프 종료)
+func (c *wsClient) send(event types.WSEvent) bool {
+	if err := c.conn.WriteJSON(event); err != nil {
+		c.conn.Close()
+		return false
+	}
+	return true
+}
+
+// ============================================================================
+// 상태 전송 (State Push)
+// ============================================================================
+
+// pushLoop 상태 브로커 구독 → state/state_delta 전송, 하트비트 Ping
+func (c *wsClient) pushLoop() {
+	broker := c.srv.api.state
+	updates, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	unwatch := broker.Watch() // 연결되어 있는 동안 유휴 주기로 늦추지 않음
+	defer unwatch()
+
+	ticker := time.NewTicker(c.srv.heartbeat)
+	defer ticker.Stop()
+
+	hello := map[string]interface{}{
+		"owner":              c.owner,
+		"heartbeat_interval": c.srv.cfg.HeartbeatInterval,
+		"model":              c.srv.api.model.Name(),
+	}
+	if !c.send(types.WSEvent{Type: "hello", Data: hello}) {
+		return
+	}
+
+	var last *types.JogState
+	failed := false
+	push := func(snap robot.StateSnapshot) bool {
+		if snap.Err != nil {
+			// 오류는 상태가 바뀔 때 한 번만 알리고, 복구되면 전체 상태부터 다시 전송
+			last = nil
+			if failed {
+				return true
+			}
+			failed = true
+			return c.send(types.WSEvent{Type: "state_error", Message: snap.Err.Error()})
+		}
+		if snap.State == nil {
+			return true
+		}
+		failed = false
+
+		event := types.WSEvent{Seq: snap.Seq, Timestamp: snap.State.Meta.Timestamp}
+		if last == nil {
+			state := *snap.State
+			state.Meta.AgeMs = snap.Age().Milliseconds()
+			event.Type, event.State = "state", &state
+		} else {
+			event.Type, event.Changes = "state_delta", stateDelta(last, snap.State)
+			if len(event.Changes) == 0 {
+				return true
+			}
+		}
+		last = snap.State
+		return c.send(event)
+	}
+
+	if !push(broker.Latest()) {
+		return
+	}
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.Ping(); err != nil {
+				c.conn.Close()
+				return
+			}
+		case snap := <-updates:
+			if !push(snap) {
+				return
+			}
+		}
+	}
+}
+
+// stateDelta 이전 상태와 비교해 바뀐 항목만 반환
+// 배열은 통째로, status는 바뀐 필드만 포함합니다 (meta는 항상 바뀌므로 stale 판정만).
+func stateDelta(prev, cur *types.JogState) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if !reflect.DeepEqual(prev.Cartesian, cur.Cartesian) {
+		changes["cartesian"] = cur.Cartesian
+	}
+	if !reflect.DeepEqual(prev.Joint, cur.Joint) {
+		changes["joint"] = cur.Joint
+	}
+	if !reflect.DeepEqual(prev.ToolData, cur.ToolData) {
+		changes["tool"] = cur.ToolData
+	}
+	if !reflect.DeepEqual(prev.Kinematics, cur.Kinematics) {
+		changes["kinematics"] = cur.Kinematics
+	}
+	if prev.Meta.Stale != cur.Meta.Stale || prev.Meta.StaleReason != cur.Meta.StaleReason {
+		changes["meta"] = map[string]interface{}{"stale": cur.Meta.Stale, "stale_reason": cur.Meta.StaleReason}
+	}
+
+	if prev.Status != cur.Status {
+		before, after := statusFields(prev.Status), statusFields(cur.Status)
+		status := make(map[string]interface{})
+		for k, v := range after {
+			if before[k] != v {
+				status[k] = v
+			}
+		}
+		if len(status) > 0 {
+			changes["status"] = status
+		}
+	}
+	return changes
+}
+
+// statusFields 상태 필드를 JSON 이름 → 값 맵으로 변환
+func statusFields(status types.JogStatus) map[string]interface{} {
+	fields := make(map[string]interface{})
+	data, _ := json.Marshal(status)
+	json.Unmarshal(data, &fields)
+	return fields
+}
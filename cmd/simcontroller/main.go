@@ -0,0 +1,29 @@
+// ============================================================================
+// cmd/simcontroller/main.go - 가상 로봇 컨트롤러 단독 실행
+// ============================================================================
+// 시뮬레이터를 별도 프로세스로 실행합니다. 실제 로봇 대신 이 주소를
+// 컨트롤러 주소로 지정하면 하드웨어 없이 서버를 개발할 수 있습니다.
+// ============================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/nir414/go-virtual-pendant/internal/simulator"
+)
+
+// main 시뮬레이터 진입점 - 기본 포트 8090에서 실행
+func main() {
+	addr := flag.String("addr", ":8090", "시뮬레이터 리슨 주소")
+	axes := flag.Int("axes", simulator.DEFAULT_AXIS_COUNT, "보고할 축 개수")
+	flag.Parse()
+
+	sim := simulator.New(simulator.Options{AxisCount: *axes})
+
+	fmt.Printf("🤖 가상 컨트롤러 실행 중: %s (축 %d개)\n", *addr, *axes)
+	log.Fatal(http.ListenAndServe(*addr, sim.Handler()))
+}
@@ -0,0 +1,282 @@
+// ============================================================================
+// internal/clientlog/clientlog.go - 브라우저 클라이언트 로그 저장 (회전 파일)
+// ============================================================================
+// 웹 인터페이스가 보낸 로그를 JSON Lines 파일에 쌓아 두고, 작업자가 문제를
+// 알린 뒤에 세션/레벨/시각으로 찾아볼 수 있게 합니다.
+//
+// 저장 규칙:
+// - 파일: <directory>/client.jsonl (types.ClientLogEntry 한 줄씩)
+// - 파일이 max_size_mb를 넘으면 client.jsonl.1, .2, ... 로 밀어내고
+//   max_files개를 넘는 가장 오래된 파일은 삭제
+// - 수신할 때마다 파일에 바로 기록 (서버가 종료되어도 남음)
+// - 조회는 오래된 파일부터 읽어 조건에 맞는 최근 항목만 반환
+// ============================================================================
+
+package clientlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 클라이언트 로그 레벨 (브라우저 console 메서드와 같은 이름)
+const (
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+// 저장 기본값
+const (
+	FILE_NAME          = "client.jsonl"
+	DEFAULT_QUERY_MAX  = 1000  // 조회 limit 기본값
+	MAX_QUERY_LIMIT    = 10000 // 조회 limit 최대값
+	MAX_MESSAGE_LENGTH = 4096  // 이보다 긴 메시지는 잘라서 저장
+	MAX_LINE_BYTES     = 1 << 20
+)
+
+// ErrInvalidQuery 잘못된 조회 조건
+var ErrInvalidQuery = errors.New("잘못된 클라이언트 로그 조회")
+
+// levelRank 레벨 심각도 (작을수록 심각)
+var levelRank = map[string]int{LevelError: 0, LevelWarn: 1, LevelInfo: 2, LevelDebug: 3}
+
+// ParseLevel 레벨 이름 정규화 ("WARNING", "log" 같은 브라우저 별칭 포함)
+func ParseLevel(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "error", "fatal":
+		return LevelError, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "info", "log", "":
+		return LevelInfo, nil
+	case "debug", "trace", "verbose":
+		return LevelDebug, nil
+	}
+	return "", fmt.Errorf("알 수 없는 레벨: %s (error, warn, info, debug)", name)
+}
+
+// Query 조회 조건 (빈 값은 조건 없음)
+type Query struct {
+	SessionID string
+	TraceID   string
+	Level     string    // 이 레벨 이상 (error가 가장 높음)
+	Since     time.Time // 서버 수신 시각 기준
+	Limit     int       // 최근 항목부터 최대 개수 (0이면 DEFAULT_QUERY_MAX)
+}
+
+// Store 회전하는 클라이언트 로그 파일
+type Store struct {
+	dir      string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File // 처음 기록할 때 엶
+	size int64
+}
+
+// New 저장소 생성 (디렉터리는 처음 기록할 때 생성)
+func New(cfg types.ClientLogConfig) *Store {
+	return &Store{
+		dir:      cfg.Directory,
+		maxBytes: int64(cfg.MaxSizeMB) << 20,
+		maxFiles: cfg.MaxFiles,
+	}
+}
+
+// Path 현재 기록 중인 파일 경로
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, FILE_NAME)
+}
+
+// Append 항목 기록 (필요하면 먼저 파일 회전)
+func (s *Store) Append(entries []types.ClientLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.Message = truncate(entry.Message, MAX_MESSAGE_LENGTH)
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		if s.file != nil && s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+		n, err := s.file.Write(line)
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate 최대 바이트 수로 자르기 (UTF-8 문자 경계에서)
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "…"
+}
+
+// Close 파일 닫기
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// open 현재 파일을 이어쓰기로 열기 (이미 열려 있으면 그대로)
+func (s *Store) open() error {
+	if s.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+// rotate client.jsonl → .1 → .2 ... (max_files 번째는 삭제)
+func (s *Store) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file, s.size = nil, 0
+
+	os.Remove(s.rotatedPath(s.maxFiles - 1))
+	for i := s.maxFiles - 2; i >= 0; i-- {
+		if err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotatedPath i번째 파일 경로 (0은 현재 파일)
+func (s *Store) rotatedPath(i int) string {
+	if i == 0 {
+		return s.Path()
+	}
+	return s.Path() + "." + strconv.Itoa(i)
+}
+
+// ============================================================================
+// 조회 (Query)
+// ============================================================================
+
+// Query 조건에 맞는 항목을 오래된 순서로 반환 (최근 Limit개)
+func (s *Store) Query(q Query) ([]types.ClientLogEntry, error) {
+	if q.Level != "" {
+		if _, ok := levelRank[q.Level]; !ok {
+			return nil, fmt.Errorf("%w: level %q", ErrInvalidQuery, q.Level)
+		}
+	}
+	switch {
+	case q.Limit < 0 || q.Limit > MAX_QUERY_LIMIT:
+		return nil, fmt.Errorf("%w: limit은 0-%d 범위여야 합니다", ErrInvalidQuery, MAX_QUERY_LIMIT)
+	case q.Limit == 0:
+		q.Limit = DEFAULT_QUERY_MAX
+	}
+
+	// 쓰는 중인 줄을 읽지 않도록 조회 동안 기록을 멈춤
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []types.ClientLogEntry
+	for i := s.maxFiles - 1; i >= 0; i-- {
+		err := readFile(s.rotatedPath(i), func(entry types.ClientLogEntry) {
+			if q.match(entry) {
+				result = append(result, entry)
+				if len(result) > 2*q.Limit {
+					result = append(result[:0], result[len(result)-q.Limit:]...)
+				}
+			}
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result, nil
+}
+
+// match 조회 조건 확인
+func (q Query) match(entry types.ClientLogEntry) bool {
+	if q.SessionID != "" && entry.SessionID != q.SessionID {
+		return false
+	}
+	if q.TraceID != "" && entry.TraceID != q.TraceID {
+		return false
+	}
+	if q.Level != "" && levelRank[entry.Level] > levelRank[q.Level] {
+		return false
+	}
+	if !q.Since.IsZero() {
+		t, err := time.Parse(time.RFC3339Nano, entry.Time)
+		if err != nil || t.Before(q.Since) {
+			return false
+		}
+	}
+	return true
+}
+
+// readFile JSON Lines 파일의 항목마다 fn 호출 (깨진 줄은 건너뜀)
+func readFile(path string, fn func(types.ClientLogEntry)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), MAX_LINE_BYTES)
+	for sc.Scan() {
+		var entry types.ClientLogEntry
+		if json.Unmarshal(sc.Bytes(), &entry) == nil {
+			fn(entry)
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,778 @@
+// ============================================================================
+// internal/config/config.go - 계층형 설정 로더
+// ============================================================================
+// 서버와 로봇 컨트롤러 설정을 다음 순서로 덮어쓰며 구성합니다.
+//
+//   1. 기본값 (Default)
+//   2. JSON 설정 파일 (-config 플래그 또는 VP_CONFIG 환경변수)
+//   3. 환경변수 (VP_*, LOG_LEVEL, GO_ENV, DEBUG_MODE, MOCK_MODE)
+//   4. 명령줄 플래그 (명시적으로 지정된 플래그만)
+//
+// 최종 설정은 Validate로 검증되며, 문제가 있으면 항목별 오류를 모아
+// 한 번에 반환합니다.
+// ============================================================================
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// 기본 설정값
+const (
+	DEFAULT_PORT               = "8082"
+	DEFAULT_API_BASE_PATH      = "/api"
+	DEFAULT_STATIC_PATH        = "web/static"
+	DEFAULT_TEMPLATE_PATH      = "web/templates"
+	DEFAULT_CONTROLLER_ADDRESS = "192.168.0.1"
+	DEFAULT_TIMEOUT_MS         = 5000
+	DEFAULT_POLL_INTERVAL_MS   = 1000
+	DEFAULT_POLL_MIN_MS        = 50
+	DEFAULT_POLL_MAX_MS        = 5000
+	DEFAULT_FAILURES_DEGRADED  = 1
+	DEFAULT_FAILURES_DISCONN   = 3
+	DEFAULT_SUCCESSES_RECOVER  = 2
+	DEFAULT_BACKOFF_MIN_MS     = 500
+	DEFAULT_BACKOFF_MAX_MS     = 30000
+	DEFAULT_JOG_REPEAT_MS      = 30
+	DEFAULT_JOG_HEARTBEAT_MS   = 500
+	DEFAULT_MAX_JOG_STEP       = 10.0 // 웹 UI 스텝 입력 최대값과 동일
+	DEFAULT_LIMIT_STATE_AGE_MS = 0    // 자동: 기본 폴링 주기 × 1.5 (폴링이 조금 늦어도 다시 조회하지 않도록)
+	DEFAULT_WS_ENDPOINT        = "/api/ws"
+	DEFAULT_WS_MAX_CONNECTIONS = 16
+	DEFAULT_WS_HEARTBEAT_SEC   = 15
+	DEFAULT_MOTION_THRESHOLD   = 0.01
+	DEFAULT_STOP_SAMPLES       = 1
+	DEFAULT_POSITION_THRESHOLD = 0.1 // 기존 콘솔 모니터 기준
+	DEFAULT_TOOL_THRESHOLD     = 0.001
+	DEFAULT_HISTORY_SAMPLES    = 3600
+	DEFAULT_HISTORY_RETENTION  = 600 // 10분
+	DEFAULT_RECORDING_DIR      = "recordings"
+	DEFAULT_RECORDING_ACTIVE   = 4
+	DEFAULT_KINEMATICS_FILTER  = 200
+	DEFAULT_KINEMATICS_GAP     = 0 // 최대 폴링 주기 × KINEMATICS_GAP_POLLS
+	KINEMATICS_GAP_POLLS       = 3 // 조회 2회 누락까지 허용
+	DEFAULT_METRICS_ENDPOINT   = "/metrics"
+	DEFAULT_READY_MAX_AGE      = 0 // 자동: 최대 폴링 주기 × READY_AGE_POLLS (최소 MIN_READY_MAX_AGE초)
+	READY_AGE_POLLS            = 3
+	MIN_READY_MAX_AGE          = 5
+	DEFAULT_STILL_VELOCITY     = 0.05
+	DEFAULT_LOG_FORMAT         = logging.FormatText
+	DEFAULT_CLIENT_LOG_DIR     = "logs"
+	DEFAULT_CLIENT_LOG_SIZE_MB = 10
+	DEFAULT_CLIENT_LOG_FILES   = 5
+	DEFAULT_WATCHDOG_FROZEN_MS = 1000
+	DEFAULT_WATCHDOG_WINDOW_MS = 2000
+	DEFAULT_WATCHDOG_LATE_MS   = 1000
+)
+
+// 검증 범위
+const (
+	MIN_TIMEOUT_MS       = 100
+	MAX_TIMEOUT_MS       = 60000
+	MIN_POLL_INTERVAL_MS = 20
+	MAX_POLL_INTERVAL_MS = 60000
+	MIN_JOG_REPEAT_MS    = 10
+	MAX_JOG_REPEAT_MS    = 1000
+	MAX_JOG_HEARTBEAT_MS = 10000
+	MIN_LIMIT_STATE_AGE  = 10
+	MAX_LIMIT_STATE_AGE  = 5000
+	MAX_WS_CONNECTIONS   = 1000
+	MAX_WS_HEARTBEAT_SEC = 300
+	MAX_STOP_SAMPLES     = 100
+	MAX_HISTORY_SAMPLES  = 1000000
+	MAX_HISTORY_SEC      = 86400
+	MAX_RECORDING_ACTIVE = 32
+	MAX_KINEMATICS_MS    = 600000 // 최대 폴링 주기보다 길어야 함
+	MAX_READY_MAX_AGE    = 3600
+	MAX_CLIENT_LOG_MB    = 1024
+	MAX_CLIENT_LOG_FILES = 100
+	MAX_WATCHDOG_MS      = 60000
+)
+
+// 환경변수 이름
+const (
+	ENV_CONFIG_FILE        = "VP_CONFIG"
+	ENV_HOST               = "VP_HOST"
+	ENV_PORT               = "VP_PORT"
+	ENV_CONTROLLER_ADDRESS = "VP_CONTROLLER_ADDRESS"
+	ENV_CONTROLLER_TIMEOUT = "VP_CONTROLLER_TIMEOUT"
+	ENV_POLL_INTERVAL      = "VP_POLL_INTERVAL"
+	ENV_ADAPTIVE_POLL      = "VP_ADAPTIVE_POLL"
+	ENV_SIMULATE           = "VP_SIMULATE"
+	ENV_LOG_LEVEL          = "LOG_LEVEL"
+	ENV_LOG_FORMAT         = "VP_LOG_FORMAT"
+	ENV_GO_ENV             = "GO_ENV"
+	ENV_DEBUG_MODE         = "DEBUG_MODE"
+	ENV_MOCK_MODE          = "MOCK_MODE"
+	ENV_STATIC_PATH        = "VP_STATIC_PATH"
+	ENV_TEMPLATE_PATH      = "VP_TEMPLATE_PATH"
+	ENV_ENABLE_CORS        = "VP_ENABLE_CORS"
+	ENV_FOLLOW_REDIRECT    = "VP_FOLLOW_REDIRECT"
+	ENV_JOG_REPEAT         = "VP_JOG_REPEAT"
+	ENV_JOG_HEARTBEAT      = "VP_JOG_HEARTBEAT_TIMEOUT"
+	ENV_LIMITS_ENABLED     = "VP_LIMITS_ENABLED"
+	ENV_ROBOT_MODEL        = "VP_ROBOT_MODEL"
+	ENV_PAYLOAD_LAYOUT     = "VP_PAYLOAD_LAYOUT"
+	ENV_ENABLE_WSS         = "VP_ENABLE_WSS"
+	ENV_RECORDING_DIR      = "VP_RECORDING_DIR"
+	ENV_CLIENT_LOG_DIR     = "VP_CLIENT_LOG_DIR"
+	ENV_WATCHDOG_BLOCK_JOG = "VP_WATCHDOG_BLOCK_JOG"
+)
+
+// ============================================================================
+// 기본값 (Defaults)
+// ============================================================================
+
+// Default 기본 설정 반환
+func Default() types.AppConfig {
+	return types.AppConfig{
+		Server: types.ServerConfig{
+			Port:         DEFAULT_PORT,
+			Host:         "", // 모든 인터페이스에서 수신
+			APIBasePath:  DEFAULT_API_BASE_PATH,
+			Environment:  types.EnvDevelopment,
+			Platform:     types.PlatformGoServer,
+			StaticPath:   DEFAULT_STATIC_PATH,
+			TemplatePath: DEFAULT_TEMPLATE_PATH,
+			LogLevel:     types.LogLevelInfo,
+			LogFormat:    DEFAULT_LOG_FORMAT,
+			EnableWSS:    true,
+		},
+		Controller: types.ControllerConfig{
+			Address:        DEFAULT_CONTROLLER_ADDRESS,
+			TimeoutMs:      DEFAULT_TIMEOUT_MS,
+			PollIntervalMs: DEFAULT_POLL_INTERVAL_MS,
+
+			AdaptivePoll:      true,
+			PollMinIntervalMs: DEFAULT_POLL_MIN_MS,
+			PollMaxIntervalMs: DEFAULT_POLL_MAX_MS,
+
+			FailuresToDegraded:   DEFAULT_FAILURES_DEGRADED,
+			FailuresToDisconnect: DEFAULT_FAILURES_DISCONN,
+			SuccessesToRecover:   DEFAULT_SUCCESSES_RECOVER,
+			BackoffMinMs:         DEFAULT_BACKOFF_MIN_MS,
+			BackoffMaxMs:         DEFAULT_BACKOFF_MAX_MS,
+		},
+		Jog: types.JogConfig{
+			RepeatIntervalMs:   DEFAULT_JOG_REPEAT_MS,
+			HeartbeatTimeoutMs: DEFAULT_JOG_HEARTBEAT_MS,
+		},
+		Limits: types.SoftLimitConfig{
+			MaxStep:       DEFAULT_MAX_JOG_STEP,
+			MaxStateAgeMs: DEFAULT_LIMIT_STATE_AGE_MS,
+		},
+		WebSocket: types.WebSocketConfig{
+			Enable:            true,
+			Endpoint:          DEFAULT_WS_ENDPOINT,
+			MaxConnections:    DEFAULT_WS_MAX_CONNECTIONS,
+			HeartbeatInterval: DEFAULT_WS_HEARTBEAT_SEC,
+		},
+		Detector: types.DetectorConfig{
+			MotionThreshold:   DEFAULT_MOTION_THRESHOLD,
+			StopSamples:       DEFAULT_STOP_SAMPLES,
+			PositionThreshold: DEFAULT_POSITION_THRESHOLD,
+			ToolThreshold:     DEFAULT_TOOL_THRESHOLD,
+		},
+		History: types.HistoryConfig{
+			MaxSamples:   DEFAULT_HISTORY_SAMPLES,
+			RetentionSec: DEFAULT_HISTORY_RETENTION,
+		},
+		Recording: types.RecordingConfig{
+			Directory: DEFAULT_RECORDING_DIR,
+			MaxActive: DEFAULT_RECORDING_ACTIVE,
+		},
+		Kinematics: types.KinematicsConfig{
+			FilterMs:      DEFAULT_KINEMATICS_FILTER,
+			MaxGapMs:      DEFAULT_KINEMATICS_GAP,
+			StillVelocity: DEFAULT_STILL_VELOCITY,
+		},
+		Metrics: types.MetricsConfig{
+			Enable:   true,
+			Endpoint: DEFAULT_METRICS_ENDPOINT,
+		},
+		Health: types.HealthConfig{
+			ReadyMaxAgeSec: DEFAULT_READY_MAX_AGE,
+		},
+		ClientLog: types.ClientLogConfig{
+			Directory: DEFAULT_CLIENT_LOG_DIR,
+			MaxSizeMB: DEFAULT_CLIENT_LOG_SIZE_MB,
+			MaxFiles:  DEFAULT_CLIENT_LOG_FILES,
+		},
+		Watchdog: types.WatchdogConfig{
+			Enabled:     true,
+			FrozenMs:    DEFAULT_WATCHDOG_FROZEN_MS,
+			JogWindowMs: DEFAULT_WATCHDOG_WINDOW_MS,
+			LatePollMs:  DEFAULT_WATCHDOG_LATE_MS,
+		},
+	}
+}
+
+// ============================================================================
+// 로더 (Loader)
+// ============================================================================
+
+// Load 기본값 → 설정 파일 → 환경변수 → 플래그 순서로 설정을 구성하고 검증
+func Load(args []string) (*types.AppConfig, error) {
+	fs, flags := newFlagSet(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	// 2. 설정 파일 (플래그가 환경변수보다 우선)
+	path := os.Getenv(ENV_CONFIG_FILE)
+	if flags.configFile != "" {
+		path = flags.configFile
+	}
+	if path != "" {
+		if err := LoadFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 환경변수
+	if err := applyEnv(&cfg, os.Getenv); err != nil {
+		return nil, err
+	}
+
+	// 4. 명시적으로 지정된 플래그
+	if err := flags.apply(fs, &cfg); err != nil {
+		return nil, err
+	}
+
+	if err := Validate(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// LoadFile JSON 설정 파일을 cfg 위에 덮어쓰기 (파일에 없는 항목은 유지)
+func LoadFile(path string, cfg *types.AppConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("설정 파일 읽기 실패 (%s): %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("설정 파일 파싱 실패 (%s): %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv 환경변수 값을 cfg에 적용
+func applyEnv(cfg *types.AppConfig, getenv func(string) string) error {
+	var errs []error
+
+	setString := func(name string, dst *string) {
+		if v := getenv(name); v != "" {
+			*dst = v
+		}
+	}
+	setBool := func(name string, dst *bool) {
+		if v := getenv(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: true/false 값이어야 합니다 (값: %q)", name, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	setDurationMs := func(name string, dst *int) {
+		if v := getenv(name); v != "" {
+			ms, err := parseDurationMs(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", name, err))
+				return
+			}
+			*dst = ms
+		}
+	}
+
+	setString(ENV_HOST, &cfg.Server.Host)
+	setString(ENV_PORT, &cfg.Server.Port)
+	setString(ENV_STATIC_PATH, &cfg.Server.StaticPath)
+	setString(ENV_TEMPLATE_PATH, &cfg.Server.TemplatePath)
+	setString(ENV_LOG_FORMAT, &cfg.Server.LogFormat)
+	setBool(ENV_ENABLE_CORS, &cfg.Server.EnableCORS)
+	setBool(ENV_ENABLE_WSS, &cfg.Server.EnableWSS)
+	setBool(ENV_DEBUG_MODE, &cfg.Server.DebugMode)
+	setString(ENV_CONTROLLER_ADDRESS, &cfg.Controller.Address)
+	setDurationMs(ENV_CONTROLLER_TIMEOUT, &cfg.Controller.TimeoutMs)
+	setDurationMs(ENV_POLL_INTERVAL, &cfg.Controller.PollIntervalMs)
+	setBool(ENV_ADAPTIVE_POLL, &cfg.Controller.AdaptivePoll)
+	// launch.json "🧪 Test Mode"의 MOCK_MODE는 시뮬레이터 모드로 해석 (VP_SIMULATE 우선)
+	setBool(ENV_MOCK_MODE, &cfg.Controller.Simulate)
+	setBool(ENV_SIMULATE, &cfg.Controller.Simulate)
+	setBool(ENV_FOLLOW_REDIRECT, &cfg.Controller.FollowRedirect)
+	setString(ENV_ROBOT_MODEL, &cfg.Controller.Model)
+	setString(ENV_PAYLOAD_LAYOUT, &cfg.Controller.PayloadLayout)
+	setString(ENV_RECORDING_DIR, &cfg.Recording.Directory)
+	setString(ENV_CLIENT_LOG_DIR, &cfg.ClientLog.Directory)
+	setBool(ENV_WATCHDOG_BLOCK_JOG, &cfg.Watchdog.BlockJog)
+	setDurationMs(ENV_JOG_REPEAT, &cfg.Jog.RepeatIntervalMs)
+	setDurationMs(ENV_JOG_HEARTBEAT, &cfg.Jog.HeartbeatTimeoutMs)
+	setBool(ENV_LIMITS_ENABLED, &cfg.Limits.Enabled)
+
+	if v := getenv(ENV_GO_ENV); v != "" {
+		cfg.Server.Environment = types.Environment(v)
+	}
+	if v := getenv(ENV_LOG_LEVEL); v != "" {
+		level, err := types.ParseLogLevel(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", ENV_LOG_LEVEL, err))
+		} else {
+			cfg.Server.LogLevel = level
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ============================================================================
+// 명령줄 플래그 (Command-Line Flags)
+// ============================================================================
+
+// cliFlags 명령줄 플래그 값
+type cliFlags struct {
+	configFile    string
+	host          string
+	port          string
+	controller    string
+	timeout       time.Duration
+	pollInterval  time.Duration
+	simulate      bool
+	model         string
+	payloadLayout string
+	logLevel      string
+	logFormat     string
+	environment   string
+	debug         bool
+}
+
+// newFlagSet 서버 플래그 정의
+func newFlagSet(output io.Writer) (*flag.FlagSet, *cliFlags) {
+	f := &cliFlags{}
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	fs.StringVar(&f.configFile, "config", "", "JSON 설정 파일 경로 (환경변수 "+ENV_CONFIG_FILE+")")
+	fs.StringVar(&f.host, "host", "", "웹 서버 바인드 주소 (빈 값이면 모든 인터페이스)")
+	fs.StringVar(&f.port, "port", DEFAULT_PORT, "웹 서버 포트")
+	fs.StringVar(&f.controller, "controller", DEFAULT_CONTROLLER_ADDRESS, "로봇 컨트롤러 주소 (예: 192.168.0.1, http://10.0.3.1:80)")
+	fs.DurationVar(&f.timeout, "timeout", DEFAULT_TIMEOUT_MS*time.Millisecond, "컨트롤러 HTTP 요청 타임아웃")
+	fs.DurationVar(&f.pollInterval, "poll", DEFAULT_POLL_INTERVAL_MS*time.Millisecond, "로봇 상태 모니터링 주기")
+	fs.BoolVar(&f.simulate, "sim", false, "실제 로봇 대신 내장 가상 컨트롤러 사용")
+	fs.StringVar(&f.model, "model", "", "로봇 모델 파일 경로 (비어 있으면 내장 6축 모델, 환경변수 "+ENV_ROBOT_MODEL+")")
+	fs.StringVar(&f.payloadLayout, "payload-layout", "", "상태 응답 레이아웃 이름 또는 파일 경로 (비어 있으면 jogrefresh-v1, 환경변수 "+ENV_PAYLOAD_LAYOUT+")")
+	fs.StringVar(&f.logLevel, "log-level", "INFO", "로그 레벨 (INFO, DEBUG, VERBOSE)")
+	fs.StringVar(&f.logFormat, "log-format", DEFAULT_LOG_FORMAT, "로그 출력 형식 (text, json - 환경변수 "+ENV_LOG_FORMAT+")")
+	fs.StringVar(&f.environment, "env", string(types.EnvDevelopment), "실행 환경 (development, production, test, debug)")
+	fs.BoolVar(&f.debug, "debug", false, "디버그 모드")
+
+	return fs, f
+}
+
+// apply 명시적으로 지정된 플래그만 cfg에 적용
+func (f *cliFlags) apply(fs *flag.FlagSet, cfg *types.AppConfig) error {
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "host":
+			cfg.Server.Host = f.host
+		case "port":
+			cfg.Server.Port = f.port
+		case "controller":
+			cfg.Controller.Address = f.controller
+		case "timeout":
+			cfg.Controller.TimeoutMs = int(f.timeout / time.Millisecond)
+		case "poll":
+			cfg.Controller.PollIntervalMs = int(f.pollInterval / time.Millisecond)
+		case "sim":
+			cfg.Controller.Simulate = f.simulate
+		case "model":
+			cfg.Controller.Model = f.model
+		case "payload-layout":
+			cfg.Controller.PayloadLayout = f.payloadLayout
+		case "log-level":
+			level, parseErr := types.ParseLogLevel(f.logLevel)
+			if parseErr != nil {
+				err = fmt.Errorf("-log-level: %v", parseErr)
+				return
+			}
+			cfg.Server.LogLevel = level
+		case "log-format":
+			cfg.Server.LogFormat = f.logFormat
+		case "env":
+			cfg.Server.Environment = types.Environment(f.environment)
+		case "debug":
+			cfg.Server.DebugMode = f.debug
+		}
+	})
+	return err
+}
+
+// ============================================================================
+// 검증 (Validation)
+// ============================================================================
+
+// Validate 설정값 검증 (모든 오류를 모아서 반환)
+func Validate(cfg *types.AppConfig) error {
+	var errs []error
+	fail := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	// 서버 설정
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port", "1-65535 범위의 숫자여야 합니다 (값: %q)", cfg.Server.Port)
+	}
+	if !strings.HasPrefix(cfg.Server.APIBasePath, "/") {
+		fail("server.api_base_path", "'/'로 시작해야 합니다 (값: %q)", cfg.Server.APIBasePath)
+	}
+	switch cfg.Server.Environment {
+	case types.EnvDevelopment, types.EnvProduction, types.EnvTest, types.EnvDebug:
+	default:
+		fail("server.environment", "development, production, test, debug 중 하나여야 합니다 (값: %q)", cfg.Server.Environment)
+	}
+	if cfg.Server.StaticPath == "" {
+		fail("server.static_path", "비어 있을 수 없습니다")
+	}
+	if cfg.Server.TemplatePath == "" {
+		fail("server.template_path", "비어 있을 수 없습니다")
+	}
+	if !logging.ValidFormat(cfg.Server.LogFormat) {
+		fail("server.log_format", "text 또는 json이어야 합니다 (값: %q)", cfg.Server.LogFormat)
+	}
+
+	// 컨트롤러 설정 (시뮬레이터 모드에서는 주소를 사용하지 않음)
+	if !cfg.Controller.Simulate {
+		if err := validateAddress(cfg.Controller.Address); err != nil {
+			fail("controller.address", "%v", err)
+		}
+	}
+	if cfg.Controller.TimeoutMs < MIN_TIMEOUT_MS || cfg.Controller.TimeoutMs > MAX_TIMEOUT_MS {
+		fail("controller.timeout_ms", "%d-%d 범위여야 합니다 (값: %d)", MIN_TIMEOUT_MS, MAX_TIMEOUT_MS, cfg.Controller.TimeoutMs)
+	}
+	if cfg.Controller.PollIntervalMs < MIN_POLL_INTERVAL_MS || cfg.Controller.PollIntervalMs > MAX_POLL_INTERVAL_MS {
+		fail("controller.poll_interval_ms", "%d-%d 범위여야 합니다 (값: %d)", MIN_POLL_INTERVAL_MS, MAX_POLL_INTERVAL_MS, cfg.Controller.PollIntervalMs)
+	}
+	if cfg.Controller.AdaptivePoll {
+		if cfg.Controller.PollMinIntervalMs < MIN_POLL_INTERVAL_MS || cfg.Controller.PollMinIntervalMs > MAX_POLL_INTERVAL_MS {
+			fail("controller.poll_min_interval_ms", "%d-%d 범위여야 합니다 (값: %d)", MIN_POLL_INTERVAL_MS, MAX_POLL_INTERVAL_MS, cfg.Controller.PollMinIntervalMs)
+		}
+		if cfg.Controller.PollMaxIntervalMs < cfg.Controller.PollMinIntervalMs || cfg.Controller.PollMaxIntervalMs > MAX_POLL_INTERVAL_MS {
+			fail("controller.poll_max_interval_ms", "poll_min_interval_ms(%d)-%d 범위여야 합니다 (값: %d)", cfg.Controller.PollMinIntervalMs, MAX_POLL_INTERVAL_MS, cfg.Controller.PollMaxIntervalMs)
+		}
+	}
+
+	// 연결 상태 판정
+	c := cfg.Controller
+	if c.FailuresToDegraded < 1 {
+		fail("controller.failures_to_degraded", "1 이상이어야 합니다 (값: %d)", c.FailuresToDegraded)
+	}
+	if c.FailuresToDisconnect < c.FailuresToDegraded {
+		fail("controller.failures_to_disconnect", "failures_to_degraded(%d) 이상이어야 합니다 (값: %d)", c.FailuresToDegraded, c.FailuresToDisconnect)
+	}
+	if c.SuccessesToRecover < 1 {
+		fail("controller.successes_to_recover", "1 이상이어야 합니다 (값: %d)", c.SuccessesToRecover)
+	}
+	if c.BackoffMinMs < 1 || c.BackoffMaxMs < c.BackoffMinMs {
+		fail("controller.backoff_min_ms", "1 이상이고 backoff_max_ms(%d) 이하여야 합니다 (값: %d)", c.BackoffMaxMs, c.BackoffMinMs)
+	}
+
+	// 연속 JOG 세션 (하트비트 창은 반복 간격의 2배 이상)
+	j := cfg.Jog
+	if j.RepeatIntervalMs < MIN_JOG_REPEAT_MS || j.RepeatIntervalMs > MAX_JOG_REPEAT_MS {
+		fail("jog.repeat_interval_ms", "%d-%d 범위여야 합니다 (값: %d)", MIN_JOG_REPEAT_MS, MAX_JOG_REPEAT_MS, j.RepeatIntervalMs)
+	}
+	if j.HeartbeatTimeoutMs < 2*j.RepeatIntervalMs || j.HeartbeatTimeoutMs > MAX_JOG_HEARTBEAT_MS {
+		fail("jog.heartbeat_timeout_ms", "repeat_interval_ms의 2배(%d) 이상 %d 이하여야 합니다 (값: %d)", 2*j.RepeatIntervalMs, MAX_JOG_HEARTBEAT_MS, j.HeartbeatTimeoutMs)
+	}
+
+	// 소프트 리밋 (축 이름은 robot.NewLimitedController에서 검증)
+	l := cfg.Limits
+	if math.IsNaN(l.MaxStep) || math.IsInf(l.MaxStep, 0) || l.MaxStep < 0 {
+		fail("limits.max_step", "0 이상의 숫자여야 합니다 (값: %v)", l.MaxStep)
+	}
+	if l.MaxStateAgeMs != 0 && (l.MaxStateAgeMs < MIN_LIMIT_STATE_AGE || l.MaxStateAgeMs > MAX_LIMIT_STATE_AGE) {
+		fail("limits.max_state_age_ms", "0(자동) 또는 %d-%d 범위여야 합니다 (값: %d)", MIN_LIMIT_STATE_AGE, MAX_LIMIT_STATE_AGE, l.MaxStateAgeMs)
+	}
+	validateAxisLimits("limits.joints", l.Joints, fail)
+	validateAxisLimits("limits.cartesian", l.Cartesian, fail)
+
+	// WebSocket (비활성화되어 있으면 검사하지 않음)
+	ws := cfg.WebSocket
+	if cfg.Server.EnableWSS && ws.Enable {
+		if !strings.HasPrefix(ws.Endpoint, "/") {
+			fail("websocket.endpoint", "'/'로 시작해야 합니다 (값: %q)", ws.Endpoint)
+		}
+		if ws.MaxConnections < 1 || ws.MaxConnections > MAX_WS_CONNECTIONS {
+			fail("websocket.max_connections", "1-%d 범위여야 합니다 (값: %d)", MAX_WS_CONNECTIONS, ws.MaxConnections)
+		}
+		if ws.HeartbeatInterval < 1 || ws.HeartbeatInterval > MAX_WS_HEARTBEAT_SEC {
+			fail("websocket.heartbeat_interval", "1-%d 범위의 초여야 합니다 (값: %d)", MAX_WS_HEARTBEAT_SEC, ws.HeartbeatInterval)
+		}
+	}
+
+	// 상태 이벤트 감지 임계값
+	d := cfg.Detector
+	thresholds := []struct {
+		field string
+		value float64
+	}{
+		{"detector.motion_threshold", d.MotionThreshold},
+		{"detector.position_threshold", d.PositionThreshold},
+		{"detector.tool_threshold", d.ToolThreshold},
+	}
+	for _, t := range thresholds {
+		if math.IsNaN(t.value) || math.IsInf(t.value, 0) || t.value < 0 {
+			fail(t.field, "0 이상의 숫자여야 합니다 (값: %v)", t.value)
+		}
+	}
+	if d.StopSamples < 1 || d.StopSamples > MAX_STOP_SAMPLES {
+		fail("detector.stop_samples", "1-%d 범위여야 합니다 (값: %d)", MAX_STOP_SAMPLES, d.StopSamples)
+	}
+
+	// 위치 이력
+	h := cfg.History
+	if h.MaxSamples < 1 || h.MaxSamples > MAX_HISTORY_SAMPLES {
+		fail("history.max_samples", "1-%d 범위여야 합니다 (값: %d)", MAX_HISTORY_SAMPLES, h.MaxSamples)
+	}
+	if h.RetentionSec < 1 || h.RetentionSec > MAX_HISTORY_SEC {
+		fail("history.retention_sec", "1-%d 범위의 초여야 합니다 (값: %d)", MAX_HISTORY_SEC, h.RetentionSec)
+	}
+
+	// 궤적 기록 (디렉터리는 첫 기록을 시작할 때 생성)
+	rec := cfg.Recording
+	if strings.TrimSpace(rec.Directory) == "" {
+		fail("recording.directory", "비어 있을 수 없습니다")
+	}
+	if rec.MaxActive < 1 || rec.MaxActive > MAX_RECORDING_ACTIVE {
+		fail("recording.max_active", "1-%d 범위여야 합니다 (값: %d)", MAX_RECORDING_ACTIVE, rec.MaxActive)
+	}
+
+	// 속도/가속도 추정 - 공백 기준은 0(자동) 또는 폴링 주기보다 길어야 함
+	k := cfg.Kinematics
+	if k.FilterMs < 0 || k.FilterMs > MAX_KINEMATICS_MS {
+		fail("kinematics.filter_ms", "0-%d 범위의 밀리초여야 합니다 (값: %d)", MAX_KINEMATICS_MS, k.FilterMs)
+	}
+	if k.MaxGapMs != 0 && (k.MaxGapMs <= cfg.Controller.PollIntervalMs || k.MaxGapMs > MAX_KINEMATICS_MS) {
+		fail("kinematics.max_gap_ms", "0(자동) 또는 controller.poll_interval_ms(%d)보다 크고 %d 이하여야 합니다 (값: %d)", cfg.Controller.PollIntervalMs, MAX_KINEMATICS_MS, k.MaxGapMs)
+	}
+	if math.IsNaN(k.StillVelocity) || math.IsInf(k.StillVelocity, 0) || k.StillVelocity < 0 {
+		fail("kinematics.still_velocity", "0 이상의 숫자여야 합니다 (값: %v)", k.StillVelocity)
+	}
+
+	// 메트릭 (비활성화되어 있으면 검사하지 않음)
+	if cfg.Metrics.Enable && !strings.HasPrefix(cfg.Metrics.Endpoint, "/") {
+		fail("metrics.endpoint", "'/'로 시작해야 합니다 (값: %q)", cfg.Metrics.Endpoint)
+	}
+
+	// 준비 상태 - 폴링 주기보다 길어야 조회 사이에 준비 상태가 흔들리지 않음
+	if age := cfg.Health.ReadyMaxAgeSec; age != 0 && (age*1000 <= cfg.Controller.PollIntervalMs || age > MAX_READY_MAX_AGE) {
+		fail("health.ready_max_age_sec", "0(자동) 또는 controller.poll_interval_ms(%d)보다 길고 %d초 이하여야 합니다 (값: %d)", cfg.Controller.PollIntervalMs, MAX_READY_MAX_AGE, age)
+	}
+
+	// 클라이언트 로그 (디렉터리는 첫 로그를 받을 때 생성)
+	cl := cfg.ClientLog
+	if strings.TrimSpace(cl.Directory) == "" {
+		fail("client_log.directory", "비어 있을 수 없습니다")
+	}
+	if cl.MaxSizeMB < 1 || cl.MaxSizeMB > MAX_CLIENT_LOG_MB {
+		fail("client_log.max_size_mb", "1-%d 범위여야 합니다 (값: %d)", MAX_CLIENT_LOG_MB, cl.MaxSizeMB)
+	}
+	if cl.MaxFiles < 1 || cl.MaxFiles > MAX_CLIENT_LOG_FILES {
+		fail("client_log.max_files", "1-%d 범위여야 합니다 (값: %d)", MAX_CLIENT_LOG_FILES, cl.MaxFiles)
+	}
+
+	// 상태 데이터 감시
+	wd := cfg.Watchdog
+	if wd.Enabled {
+		for _, v := range []struct {
+			field string
+			ms    int
+		}{
+			{"watchdog.frozen_ms", wd.FrozenMs},
+			{"watchdog.jog_window_ms", wd.JogWindowMs},
+			{"watchdog.late_poll_ms", wd.LatePollMs},
+		} {
+			if v.ms < 1 || v.ms > MAX_WATCHDOG_MS {
+				fail(v.field, "1-%d 범위여야 합니다 (값: %d)", MAX_WATCHDOG_MS, v.ms)
+			}
+		}
+		if wd.JogWindowMs < wd.FrozenMs {
+			fail("watchdog.jog_window_ms", "frozen_ms(%d) 이상이어야 합니다 (값: %d)", wd.FrozenMs, wd.JogWindowMs)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateAxisLimits 축 허용 범위 검증 (min < max, 유한한 값)
+func validateAxisLimits(field string, limits map[string]types.AxisLimit, fail func(string, string, ...interface{})) {
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		limit := limits[name]
+		if !isFinite(limit.Min) || !isFinite(limit.Max) || limit.Min >= limit.Max {
+			fail(field+"."+name, "min < max인 유한한 값이어야 합니다 (값: %v ~ %v)", limit.Min, limit.Max)
+		}
+	}
+}
+
+// validateAddress 컨트롤러 주소 형식 검증
+func validateAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("비어 있을 수 없습니다")
+	}
+	raw := address
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("올바른 주소가 아닙니다 (값: %q)", address)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("http 또는 https 주소여야 합니다 (값: %q)", address)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("호스트가 없습니다 (값: %q)", address)
+	}
+	return nil
+}
+
+// ============================================================================
+// 유틸리티 함수 (Utility Functions)
+// ============================================================================
+
+// parseDurationMs "5s", "250ms" 같은 기간 또는 밀리초 숫자를 밀리초로 변환
+func parseDurationMs(v string) (int, error) {
+	if ms, err := strconv.Atoi(v); err == nil {
+		return ms, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("기간 형식이어야 합니다 (예: 5s, 250ms) (값: %q)", v)
+	}
+	return int(d / time.Millisecond), nil
+}
+
+// ParseMillis "250ms", "1s" 같은 기간 또는 밀리초 숫자를 time.Duration으로 변환 (쿼리 파라미터용)
+func ParseMillis(v string) (time.Duration, error) {
+	ms, err := parseDurationMs(v)
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("0 이상이어야 합니다 (값: %q)", v)
+	}
+	return Millis(ms), nil
+}
+
+// isFinite NaN/Inf가 아닌지 확인
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Timeout 컨트롤러 타임아웃을 time.Duration으로 반환
+func Timeout(cfg *types.AppConfig) time.Duration {
+	return Millis(cfg.Controller.TimeoutMs)
+}
+
+// Millis 밀리초 설정값을 time.Duration으로 변환
+func Millis(ms int) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+// PollInterval 모니터링 주기를 time.Duration으로 반환
+func PollInterval(cfg *types.AppConfig) time.Duration {
+	return Millis(cfg.Controller.PollIntervalMs)
+}
+
+// PollPolicy 적응형 폴링 주기 (adaptive_poll이 꺼져 있으면 false)
+// 기본 주기가 최소/최대 범위 밖이면 상태 브로커가 범위를 기본 주기까지 넓힙니다.
+func PollPolicy(cfg *types.AppConfig) (robot.PollPolicy, bool) {
+	if !cfg.Controller.AdaptivePoll {
+		return robot.PollPolicy{}, false
+	}
+	return robot.PollPolicy{
+		Min: Millis(cfg.Controller.PollMinIntervalMs),
+		Max: Millis(cfg.Controller.PollMaxIntervalMs),
+	}, true
+}
+
+// MaxPollInterval 가장 긴 폴링 주기 (적응형 폴링이면 유휴 최대 주기, 아니면 기본 주기)
+func MaxPollInterval(cfg *types.AppConfig) time.Duration {
+	interval := PollInterval(cfg)
+	if policy, ok := PollPolicy(cfg); ok && policy.Max > interval {
+		return policy.Max
+	}
+	return interval
+}
+
+// LimitStateMaxAge 소프트 리밋 검사에 쓸 위치의 최대 나이
+// max_state_age_ms가 0이면 기본 폴링 주기 × 1.5로 계산하여, 폴링 결과를 그대로 쓰고
+// 유휴 상태로 폴링이 느려졌을 때만 JOG 전에 다시 조회합니다.
+func LimitStateMaxAge(cfg *types.AppConfig) time.Duration {
+	if cfg.Limits.MaxStateAgeMs > 0 {
+		return Millis(cfg.Limits.MaxStateAgeMs)
+	}
+	interval := PollInterval(cfg)
+	age := interval + interval/2
+	if age > MAX_LIMIT_STATE_AGE*time.Millisecond {
+		age = MAX_LIMIT_STATE_AGE * time.Millisecond
+	}
+	return age
+}
+
+// ReadyMaxAge 준비 상태 판정 기준 (ready_max_age_sec가 0이면 최대 폴링 주기로 계산)
+func ReadyMaxAge(cfg *types.AppConfig) time.Duration {
+	if cfg.Health.ReadyMaxAgeSec > 0 {
+		return time.Duration(cfg.Health.ReadyMaxAgeSec) * time.Second
+	}
+	age := MaxPollInterval(cfg) * READY_AGE_POLLS
+	if age < MIN_READY_MAX_AGE*time.Second {
+		age = MIN_READY_MAX_AGE * time.Second
+	}
+	return age
+}
+
+// Kinematics 속도/가속도 추정 설정 (max_gap_ms가 0이면 최대 폴링 주기로 계산)
+// 유휴 주기에서도 추정이 이어져야 움직이기 시작할 때 빠른 주기로 바꿀 수 있습니다.
+func Kinematics(cfg *types.AppConfig) types.KinematicsConfig {
+	k := cfg.Kinematics
+	if k.MaxGapMs == 0 {
+		k.MaxGapMs = int(MaxPollInterval(cfg)/time.Millisecond) * KINEMATICS_GAP_POLLS
+	}
+	return k
+}
@@ -0,0 +1,256 @@
+// ============================================================================
+// internal/logging/logging.go - 구조화된 로그 (텍스트 / JSON)
+// ============================================================================
+// 서버 전체가 공유하는 로거입니다. 레코드는 types.LogEntry 형식이며
+// 출력 형식은 두 가지입니다.
+//
+// - text: 사람이 읽는 한 줄 (시각, 레벨 아이콘, 메시지, key=value 필드, trace=)
+// - json: types.LogEntry를 한 줄에 하나씩 (로그 수집기용)
+//
+// 레벨과 형식은 실행 중에 바꿀 수 있습니다 (PUT /api/admin/loglevel).
+// 레벨 확인은 원자적으로 읽으므로 꺼진 레벨의 로그는 비용이 거의 없습니다.
+// ============================================================================
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 출력 형식
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// TEXT_TIME_LAYOUT 텍스트 형식 시각 (기존 log 패키지 기본 형식과 동일)
+const TEXT_TIME_LAYOUT = "2006/01/02 15:04:05"
+
+// Fields 로그 레코드의 추가 필드 (axis, mode, latency_ms 등)
+type Fields map[string]interface{}
+
+// Logger 레벨과 형식을 실행 중에 바꿀 수 있는 로거
+type Logger struct {
+	level    atomic.Int32 // types.LogLevel
+	json     atomic.Bool  // true면 JSON 형식
+	platform types.Platform
+
+	mu  sync.Mutex // out 쓰기 직렬화
+	out io.Writer
+}
+
+// New 로거 생성 (INFO 레벨, 텍스트 형식)
+func New(out io.Writer, platform types.Platform) *Logger {
+	l := &Logger{out: out, platform: platform}
+	l.level.Store(int32(types.LogLevelInfo))
+	return l
+}
+
+// std 서버 기본 로거 (표준 오류 출력)
+var std = New(os.Stderr, types.PlatformGoServer)
+
+// Default 서버 기본 로거
+func Default() *Logger {
+	return std
+}
+
+// ValidFormat 지원하는 출력 형식인지 확인
+func ValidFormat(format string) bool {
+	return format == FormatText || format == FormatJSON
+}
+
+// SetLevel 로그 레벨 변경 (이전 레벨 반환)
+func (l *Logger) SetLevel(level types.LogLevel) types.LogLevel {
+	return types.LogLevel(l.level.Swap(int32(level)))
+}
+
+// Level 현재 로그 레벨
+func (l *Logger) Level() types.LogLevel {
+	return types.LogLevel(l.level.Load())
+}
+
+// Enabled 이 레벨의 로그를 출력하는지
+func (l *Logger) Enabled(level types.LogLevel) bool {
+	return level <= l.Level()
+}
+
+// SetFormat 출력 형식 변경 ("text", "json")
+func (l *Logger) SetFormat(format string) error {
+	if !ValidFormat(format) {
+		return fmt.Errorf("알 수 없는 로그 형식: %s (text, json)", format)
+	}
+	l.json.Store(format == FormatJSON)
+	return nil
+}
+
+// Format 현재 출력 형식
+func (l *Logger) Format() string {
+	if l.json.Load() {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// SetOutput 출력 대상 변경
+func (l *Logger) SetOutput(out io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = out
+}
+
+// Log 레코드 하나 출력 (레벨이 꺼져 있으면 메시지를 만들지 않음)
+func (l *Logger) Log(level types.LogLevel, traceID string, fields Fields, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	l.Write(types.LogEntry{
+		Level:    level,
+		Message:  msg,
+		Platform: l.platform,
+		TraceID:  traceID,
+		Fields:   fields,
+	})
+}
+
+// Write 완성된 레코드 출력 (레벨 확인 없음, Timestamp가 비어 있으면 현재 시각)
+func (l *Logger) Write(entry types.LogEntry) {
+	now := time.Now()
+	if entry.Timestamp == "" {
+		entry.Timestamp = now.Format(time.RFC3339Nano)
+	}
+	if entry.Platform == "" {
+		entry.Platform = l.platform
+	}
+
+	var line []byte
+	if l.json.Load() {
+		line, _ = json.Marshal(entry)
+		line = append(line, '\n')
+	} else {
+		line = formatText(now, entry)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(line)
+}
+
+// formatText 텍스트 형식 한 줄 (필드는 키 순서로 정렬)
+func formatText(now time.Time, entry types.LogEntry) []byte {
+	var b strings.Builder
+	b.WriteString(now.Format(TEXT_TIME_LAYOUT))
+	b.WriteByte(' ')
+	b.WriteString(levelIcon(entry.Level))
+	b.WriteString(entry.Message)
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, textValue(entry.Fields[k]))
+	}
+	if entry.TraceID != "" {
+		b.WriteString(" trace=")
+		b.WriteString(textValue(entry.TraceID))
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// levelIcon 텍스트 형식 레벨 표시 (기존 로그와 같은 아이콘)
+func levelIcon(level types.LogLevel) string {
+	switch level {
+	case types.LogLevelDebug:
+		return "🔍 "
+	case types.LogLevelVerbose:
+		return "🔧 "
+	}
+	return "ℹ️  "
+}
+
+// textValue 필드 값 (공백이나 따옴표가 있으면 따옴표로 감쌈)
+func textValue(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// ============================================================================
+// 기본 로거 함수 (Package Functions)
+// ============================================================================
+
+// SetLevel 기본 로거 레벨 변경 (이전 레벨 반환)
+func SetLevel(level types.LogLevel) types.LogLevel {
+	return std.SetLevel(level)
+}
+
+// Level 기본 로거 레벨
+func Level() types.LogLevel {
+	return std.Level()
+}
+
+// Enabled 기본 로거가 이 레벨을 출력하는지
+func Enabled(level types.LogLevel) bool {
+	return std.Enabled(level)
+}
+
+// SetFormat 기본 로거 출력 형식 변경
+func SetFormat(format string) error {
+	return std.SetFormat(format)
+}
+
+// Format 기본 로거 출력 형식
+func Format() string {
+	return std.Format()
+}
+
+// Log 기본 로거로 레코드 출력
+func Log(level types.LogLevel, traceID string, fields Fields, format string, args ...interface{}) {
+	std.Log(level, traceID, fields, format, args...)
+}
+
+// Info 정보 레벨 로그
+func Info(format string, args ...interface{}) {
+	std.Log(types.LogLevelInfo, "", nil, format, args...)
+}
+
+// Debug 디버그 레벨 로그
+func Debug(format string, args ...interface{}) {
+	std.Log(types.LogLevelDebug, "", nil, format, args...)
+}
+
+// Verbose 상세 레벨 로그
+func Verbose(format string, args ...interface{}) {
+	std.Log(types.LogLevelVerbose, "", nil, format, args...)
+}
+
+// Millis 지연 필드 값 (밀리초, 소수점 셋째 자리)
+func Millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,342 @@
+// ============================================================================
+// internal/metrics/metrics.go - Prometheus 텍스트 형식 메트릭 (표준 라이브러리)
+// ============================================================================
+// 셀마다 로컬 Prometheus가 스크랩할 수 있도록 카운터, 히스토그램, 게이지를
+// Prometheus 텍스트 노출 형식(0.0.4)으로 출력합니다. 외부 클라이언트
+// 라이브러리 없이 이 서버에 필요한 만큼만 구현합니다.
+//
+// 사용 규칙:
+// - 메트릭은 시작할 때 Registry에 등록 (이름은 vp_ 접두사, 단위 접미사)
+// - 카운터/히스토그램은 레이블 값 조합마다 시계열을 만듦 (레이블 값은 고정된 집합만 사용)
+// - 게이지는 스크랩할 때 콜백으로 현재 값을 읽음 (GaugeFunc)
+// - 출력은 등록 순서, 시계열은 레이블 값 순서로 정렬
+// ============================================================================
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CONTENT_TYPE Prometheus 텍스트 노출 형식
+const CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+
+// DEFAULT_LATENCY_BUCKETS 컨트롤러 요청 지연 히스토그램 구간 (초)
+var DEFAULT_LATENCY_BUCKETS = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metric 등록된 메트릭 하나
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry 메트릭 목록과 텍스트 출력
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// NewRegistry 빈 레지스트리 생성
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// register 메트릭 등록 (같은 이름을 두 번 등록하면 패닉 - 시작 시 코드 오류)
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: 중복된 메트릭 이름: " + name)
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText 모든 메트릭을 텍스트 형식으로 기록
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler GET 스크랩 핸들러
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", CONTENT_TYPE)
+		r.WriteText(w)
+	})
+}
+
+// ============================================================================
+// 시계열 (Series)
+// ============================================================================
+
+// family 레이블 값 조합별 시계열 모음
+type family struct {
+	name   string
+	help   string
+	kind   string // "counter", "gauge", "histogram"
+	labels []string
+}
+
+// header HELP/TYPE 줄 기록
+func (f *family) header(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+}
+
+// sample 시계열 한 줄 기록 (extra는 히스토그램 le 등 추가 레이블)
+func (f *family) sample(w *bufio.Writer, suffix string, values []string, extraName, extraValue string, v float64) {
+	w.WriteString(f.name)
+	w.WriteString(suffix)
+	if len(values) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, name := range f.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, name, values[i])
+		}
+		if extraName != "" {
+			if len(f.labels) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+// key 레이블 값 개수 확인 후 시계열 키 반환
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s 레이블 값은 %d개여야 합니다 (받은 값 %d개)", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// sortedKeys 시계열 키 정렬
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ============================================================================
+// 카운터 (Counter)
+// ============================================================================
+
+// Counter 단조 증가 카운터 (레이블 값 조합별)
+type Counter struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+	series map[string][]string // 키 → 레이블 값
+}
+
+// Counter 카운터 등록
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{
+		family: family{name: name, help: help, kind: "counter", labels: labels},
+		values: make(map[string]float64),
+		series: make(map[string][]string),
+	}
+	r.register(name, c)
+	return c
+}
+
+// Inc 1 증가
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add v만큼 증가 (음수는 무시)
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.series[key]; !ok {
+		c.series[key] = append([]string(nil), labelValues...)
+	}
+	c.values[key] += v
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w)
+	for _, key := range sortedKeys(c.values) {
+		c.sample(w, "", c.series[key], "", "", c.values[key])
+	}
+}
+
+// ============================================================================
+// 히스토그램 (Histogram)
+// ============================================================================
+
+// histogramSeries 레이블 값 조합 하나의 구간별 개수
+type histogramSeries struct {
+	labels []string
+	counts []uint64 // 구간별 (누적 아님), 마지막은 +Inf
+	sum    float64
+	count  uint64
+}
+
+// Histogram 관측값 분포 (레이블 값 조합별)
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+// Histogram 히스토그램 등록 (buckets는 오름차순 상한값)
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{
+		family:  family{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(name, h)
+	return h
+}
+
+// Observe 관측값 추가
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			labels: append([]string(nil), labelValues...),
+			counts: make([]uint64, len(h.buckets)+1),
+		}
+		h.series[key] = s
+	}
+	i := sort.SearchFloat64s(h.buckets, v) // v 이상인 첫 상한 (le 구간)
+	s.counts[i]++
+	s.sum += v
+	s.count++
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			h.sample(w, "_bucket", s.labels, "le", formatFloat(upper), float64(cumulative))
+		}
+		h.sample(w, "_bucket", s.labels, "le", "+Inf", float64(s.count))
+		h.sample(w, "_sum", s.labels, "", "", s.sum)
+		h.sample(w, "_count", s.labels, "", "", float64(s.count))
+	}
+}
+
+// ============================================================================
+// 게이지 (GaugeFunc)
+// ============================================================================
+
+// gaugeFunc 스크랩할 때 값을 읽는 게이지
+type gaugeFunc struct {
+	family
+	collect func(set func(value float64, labelValues ...string))
+}
+
+// GaugeFunc 게이지 등록 - collect는 스크랩마다 호출되어 레이블 값 조합별로 set을 호출
+func (r *Registry) GaugeFunc(name, help string, labels []string, collect func(set func(value float64, labelValues ...string))) {
+	r.register(name, &gaugeFunc{
+		family:  family{name: name, help: help, kind: "gauge", labels: labels},
+		collect: collect,
+	})
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	values := make(map[string]float64)
+	labels := make(map[string][]string)
+	g.collect(func(v float64, labelValues ...string) {
+		key := g.key(labelValues)
+		values[key] = v
+		labels[key] = append([]string(nil), labelValues...)
+	})
+
+	g.header(w)
+	for _, key := range sortedKeys(values) {
+		g.sample(w, "", labels[key], "", "", values[key])
+	}
+}
+
+// ============================================================================
+// 형식 (Formatting)
+// ============================================================================
+
+// formatFloat Prometheus 숫자 형식 (+Inf, -Inf, NaN 포함)
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeLabel name="value" 기록 (\, ", 줄바꿈 이스케이프)
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	for _, r := range value {
+		switch r {
+		case '\\':
+			w.WriteString(`\\`)
+		case '"':
+			w.WriteString(`\"`)
+		case '\n':
+			w.WriteString(`\n`)
+		default:
+			w.WriteRune(r)
+		}
+	}
+	w.WriteByte('"')
+}
+
+// escapeHelp HELP 문자열 이스케이프 (\, 줄바꿈)
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
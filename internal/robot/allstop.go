@@ -0,0 +1,178 @@
+// ============================================================================
+// internal/robot/allstop.go - 전체 정지 (All-Stop)
+// ============================================================================
+// 대기 중인 JOG 명령과 서버 측 연속 JOG 세션을 모두 취소하고 중단 명령
+// (PID1=0,0,0,0)을 즉시 전송합니다. 선택적으로 JOG를 비활성화(PID 215 = 0)
+// 하여 모드 변경으로 다시 활성화할 때까지 조깅이 꺼진 상태로 유지합니다.
+//
+// 명령 큐의 작업 고루틴, 세션 잠금, 상태 폴링을 기다리지 않고 컨트롤러로
+// 직접 전송하므로, 이전 요청이 컨트롤러 응답을 기다리며 멈춰 있어도
+// 동작합니다. 직접 전송은 작업 고루틴이 이미 전송 중인 JOG 명령을 앞지를
+// 수 있으므로, 그 명령이 끝난 뒤 우선 큐로 중단 명령을 한 번 더 보냅니다.
+// 재중단은 DEFAULT_ALL_STOP_RESTOP_WAIT만 기다리고, 더 걸리면 결과를 나중에
+// 감사 기록에 반영합니다. 정지가 끝날 때까지 큐에 장벽을 세워 새로 들어오는
+// JOG 명령은 폐기합니다. 모든 정지 요청은 요청자와 함께 감사 기록으로 남습니다.
+// ============================================================================
+
+package robot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 보관하는 전체 정지 기록 수
+const DEFAULT_STOP_RECORD_HISTORY = 100
+
+// 큐를 통한 재중단 결과를 응답 전에 기다리는 시간
+const DEFAULT_ALL_STOP_RESTOP_WAIT = 100 * time.Millisecond
+
+// 기본 정지 경로
+const stopSourceAPI = "api"
+
+// 큐를 통한 재중단 결과 (StopRecord.QueuedStop)
+const (
+	queuedStopSent    = "sent"
+	queuedStopFailed  = "failed"
+	queuedStopPending = "pending"
+)
+
+// AllStop 전체 정지 실행 및 감사 기록
+type AllStop struct {
+	queue    *QueuedController
+	sessions *JogSessionManager
+
+	mu      sync.Mutex // records, nextID 보호 (전송 중에는 잡지 않음)
+	records []types.StopRecord
+	nextID  uint64
+}
+
+// NewAllStop 전체 정지 실행기 생성
+func NewAllStop(queue *QueuedController, sessions *JogSessionManager) *AllStop {
+	return &AllStop{queue: queue, sessions: sessions}
+}
+
+// Trigger 전체 정지 실행 후 감사 기록 반환
+// remoteAddr, userAgent는 요청자 식별용이며 req.Meta.ClientID가 있으면 우선합니다.
+// Success/ErrorCode는 중단 명령 결과이고, JOG 비활성화 결과는 Disable* 필드에 따로 기록합니다.
+func (a *AllStop) Trigger(req types.StopRequest, remoteAddr string, userAgent string) types.StopRecord {
+	start := time.Now()
+
+	a.mu.Lock()
+	a.nextID++
+	id := a.nextID
+	a.mu.Unlock()
+
+	record := types.StopRecord{
+		ID:               id,
+		Time:             start.Format(time.RFC3339Nano),
+		TriggeredBy:      req.Meta.ClientID,
+		RemoteAddr:       remoteAddr,
+		UserAgent:        userAgent,
+		Source:           req.Source,
+		Reason:           req.Reason,
+		DisableRequested: req.Disable,
+	}
+	if record.TriggeredBy == "" {
+		record.TriggeredBy = remoteAddr
+	}
+	if record.Source == "" {
+		record.Source = stopSourceAPI
+	}
+
+	// 1. 장벽을 세워 아직 전송되지 않은 JOG 명령 폐기, 반복 루프 종료 (기다리지 않음)
+	record.CommandsDiscarded = a.queue.HoldJogs()
+	defer a.queue.ReleaseJogs()
+	record.SessionsStopped = a.sessions.AbortAll()
+
+	// 2. 중단 명령을 작업 고루틴을 거치지 않고 직접 전송
+	resp, err := a.queue.inner.SendJogCommand(types.JogCommand{Dir: "stop"})
+	record.Success = err == nil
+	record.Message = resp.Message
+	record.ErrorCode = resp.ErrorCode
+
+	// 3. JOG 비활성화 (중단 명령 실패 여부와 관계없이 시도)
+	if req.Disable {
+		disableResp, disableErr := a.queue.inner.DisableJog()
+		record.JogDisabled = disableErr == nil
+		record.DisableMessage = disableResp.Message
+		record.DisableErrorCode = disableResp.ErrorCode
+	}
+
+	// 4. 작업 고루틴이 전송 중이던 JOG 명령 뒤에 중단 명령을 다시 전송 (잠깐만 대기)
+	restop := make(chan queuedResult, 1)
+	go func() {
+		resp, err := a.queue.SendJogCommand(types.JogCommand{Dir: "stop"})
+		restop <- queuedResult{resp: resp, err: err}
+	}()
+	pending := false
+	select {
+	case res := <-restop:
+		applyQueuedStop(&record, res)
+	case <-time.After(DEFAULT_ALL_STOP_RESTOP_WAIT):
+		record.QueuedStop = queuedStopPending
+		pending = true
+	}
+
+	record.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	logInfo("🛑 전체 정지 #%d: 요청자=%s 경로=%s 사유=%q 성공=%v 재중단=%s 비활성화=%v 세션=%d 폐기=%d (%.1fms)",
+		record.ID, record.TriggeredBy, record.Source, record.Reason, record.Success, record.QueuedStop,
+		record.JogDisabled, record.SessionsStopped, record.CommandsDiscarded, record.DurationMs)
+
+	a.mu.Lock()
+	a.records = append(a.records, record)
+	if len(a.records) > DEFAULT_STOP_RECORD_HISTORY {
+		a.records = a.records[len(a.records)-DEFAULT_STOP_RECORD_HISTORY:]
+	}
+	a.mu.Unlock()
+
+	if pending {
+		go a.finishQueuedStop(id, restop)
+	}
+	return record
+}
+
+// Records 전체 정지 감사 기록 (최신순)
+func (a *AllStop) Records() []types.StopRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	records := make([]types.StopRecord, 0, len(a.records))
+	for i := len(a.records) - 1; i >= 0; i-- {
+		records = append(records, a.records[i])
+	}
+	return records
+}
+
+// finishQueuedStop 늦게 끝난 재중단 결과를 감사 기록에 반영
+func (a *AllStop) finishQueuedStop(id uint64, restop <-chan queuedResult) {
+	res := <-restop
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := range a.records {
+		if a.records[i].ID == id {
+			applyQueuedStop(&a.records[i], res)
+			logInfo("🛑 전체 정지 #%d 재중단 완료: %s (성공=%v)", id, a.records[i].QueuedStop, a.records[i].Success)
+			return
+		}
+	}
+}
+
+// applyQueuedStop 재중단 결과 기록 - 직접 전송이 실패했어도 재중단이 전달되면 성공
+func applyQueuedStop(record *types.StopRecord, res queuedResult) {
+	if res.err != nil {
+		record.QueuedStop = queuedStopFailed
+		logInfo("❌ 전체 정지 #%d: 큐를 통한 중단 명령 전송 실패: %v", record.ID, res.err)
+		return
+	}
+	record.QueuedStop = queuedStopSent
+	if !record.Success {
+		record.Success = true
+		record.Message = res.resp.Message
+		record.ErrorCode = ""
+	}
+}
@@ -0,0 +1,52 @@
+// ============================================================================
+// internal/robot/allstop_test.go - 전체 정지 테스트
+// ============================================================================
+// 작업 고루틴이 멈춰 있어도 바로 응답하는지, JOG 비활성화 실패가 중단
+// 결과와 섞이지 않는지 가짜 컨트롤러(queue_test.go)로 확인합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+func TestAllStopDoesNotWaitForHeldWorker(t *testing.T) {
+	q, fake, first, release := newHeldQueue(t)
+	defer release()
+	a := NewAllStop(q, NewJogSessionManager(q, JogSessionOptions{}))
+
+	start := time.Now()
+	record := a.Trigger(types.StopRequest{}, "test", "")
+	if elapsed := time.Since(start); elapsed > DEFAULT_ALL_STOP_RESTOP_WAIT+500*time.Millisecond {
+		t.Fatalf("Trigger 시간 = %v, 작업 고루틴을 기다림", elapsed)
+	}
+	if !record.Success || record.QueuedStop != queuedStopPending {
+		t.Fatalf("기록 = %+v, want success, queued_stop=%s", record, queuedStopPending)
+	}
+
+	// 전송 중이던 JOG가 끝나면 재중단이 그 뒤에 전송되고 기록에 반영
+	release()
+	recv(t, first)
+	waitFor(t, "재중단 반영", func() bool { return a.Records()[0].QueuedStop == queuedStopSent })
+	assertSent(t, fake, "jog::stop", "jog:J1:positive", "jog::stop")
+}
+
+func TestAllStopDisableFailureKeptSeparate(t *testing.T) {
+	fake := &fakeController{disableErr: errors.New("PID 215 쓰기 실패")}
+	q := NewQueuedController(fake, QueueOptions{})
+	a := NewAllStop(q, NewJogSessionManager(q, JogSessionOptions{}))
+
+	record := a.Trigger(types.StopRequest{Disable: true}, "test", "")
+	if !record.Success || record.ErrorCode != "" {
+		t.Fatalf("중단 결과 = success=%v error_code=%q, want 성공", record.Success, record.ErrorCode)
+	}
+	if record.JogDisabled || record.DisableErrorCode != types.ErrCodeRejected {
+		t.Fatalf("비활성화 결과 = jog_disabled=%v disable_error_code=%q, want 실패 %s",
+			record.JogDisabled, record.DisableErrorCode, types.ErrCodeRejected)
+	}
+}
@@ -0,0 +1,380 @@
+// ============================================================================
+// internal/robot/broker.go - 로봇 상태 브로커 (단일 폴러 + 캐시)
+// ============================================================================
+// 브라우저마다 /api/jog/state를 호출할 때마다 컨트롤러로 jogrefresh.asp를
+// 요청하면 태블릿 몇 대만 열어도 컨트롤러 부하가 커집니다. StateBroker는
+// 하나의 고루틴으로 상태를 폴링하고 마지막 JogState를 시각과 순번(Seq)과
+// 함께 보관합니다. 읽는 쪽은 캐시에서 응답받고, 필요하면 maxAge로 더
+// 최근 상태를 요구할 수 있습니다.
+//
+// 읽기 규칙:
+// - maxAge 없음: 마지막 상태 (마지막 폴링이 실패했으면 그 오류)
+// - maxAge 지정: 상태가 maxAge보다 오래되었으면 즉시 다시 조회
+// - 동시에 들어온 다시 조회 요청은 한 번의 컨트롤러 요청으로 합침
+// - 구독자(모니터 등)는 조회할 때마다 스냅샷을 받음 (느린 구독자는 최신 값만)
+// - 속도/가속도 추정기가 있으면 성공한 조회마다 JogState.Kinematics를 채움
+// - 상태 데이터 감시기가 있으면 성공한 조회마다 JogState.Meta.Stale을 판정
+//
+// 폴링 주기 (SetPollPolicy로 켬 - 조회할 때마다 다시 정함):
+// - fast: 움직이는 중(Kinematics.Moving)이거나 명령 직후(Boost) → 최소 주기
+// - watched: 상태를 보는 클라이언트(Watch, 최근 Get, Watching)가 있음 → 기본 주기
+// - idle: 둘 다 아니면 기본 주기부터 두 배씩 늘려 최대 주기까지
+// 유휴 중에 명령이나 새 클라이언트가 오면 다음 주기를 기다리지 않고 바로 조회합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 상태 브로커 기본값
+const (
+	DEFAULT_STATE_POLL_INTERVAL = time.Second
+	STATE_SOURCE                = "go-server" // StateMeta.Source
+
+	POLL_BOOST_DURATION = 2 * time.Second  // 명령 뒤 빠른 주기를 유지하는 시간 (감속까지 보도록)
+	POLL_DEMAND_WINDOW  = 10 * time.Second // 마지막 Get 이후 이 시간 동안은 보는 클라이언트가 있다고 봄
+)
+
+// 폴링 주기 상태 (PollMode)
+const (
+	PollFixed   = "fixed"   // 적응형 폴링 꺼짐
+	PollFast    = "fast"    // 움직임 / 명령 직후
+	PollWatched = "watched" // 보는 클라이언트 있음
+	PollIdle    = "idle"    // 아무도 보지 않음 (점점 느리게)
+)
+
+// PollPolicy 적응형 폴링 주기 (기본 주기는 NewStateBroker의 interval)
+type PollPolicy struct {
+	Min      time.Duration // 빠른 주기 (움직임, 명령 직후)
+	Max      time.Duration // 유휴 상태의 최대 주기
+	Watching func() bool   // Watch 외에 상태가 필요한 소비자가 있는지 (궤적 기록 등, nil이면 없음)
+}
+
+// StateSnapshot 한 번의 상태 조회 결과
+type StateSnapshot struct {
+	State *types.JogState // 마지막으로 성공한 상태 (읽기 전용으로 공유)
+	Seq   uint64          // 성공한 조회마다 1씩 증가
+	At    time.Time       // State를 읽은 시각
+	Err   error           // 이번 조회의 오류 (성공이면 nil, State는 이전 값 유지)
+}
+
+// Age 상태를 읽은 뒤 지난 시간
+func (s StateSnapshot) Age() time.Duration {
+	if s.At.IsZero() {
+		return 0
+	}
+	return time.Since(s.At)
+}
+
+// StateBroker 컨트롤러 상태를 하나의 폴러로 조회하고 캐시하는 브로커
+type StateBroker struct {
+	ctrl     Controller
+	interval time.Duration
+
+	fetchMu    sync.Mutex           // 컨트롤러 조회 직렬화 (동시 요청 합치기)
+	kinematics *KinematicsEstimator // fetchMu로 보호
+	watchdog   *StaleWatchdog       // fetchMu로 보호
+
+	mu      sync.Mutex
+	latest  StateSnapshot
+	subs    map[int]chan StateSnapshot
+	nextSub int
+
+	policy     *PollPolicy   // nil이면 고정 주기 (Run 전에 설정)
+	wake       chan struct{} // 유휴 대기 중인 Run을 깨움
+	effective  atomic.Int64  // 현재 폴링 주기 (time.Duration)
+	mode       atomic.Value  // 현재 폴링 주기 상태 (string)
+	boostUntil atomic.Int64  // 이 시각(UnixNano)까지 빠른 주기
+	demandAt   atomic.Int64  // 마지막 Get 시각 (UnixNano)
+	watchers   atomic.Int32  // Watch 중인 클라이언트 수
+}
+
+// NewStateBroker 상태 브로커 생성 (폴링은 Run으로 시작)
+func NewStateBroker(ctrl Controller, interval time.Duration) *StateBroker {
+	if interval <= 0 {
+		interval = DEFAULT_STATE_POLL_INTERVAL
+	}
+	b := &StateBroker{
+		ctrl:     ctrl,
+		interval: interval,
+		subs:     make(map[int]chan StateSnapshot),
+		wake:     make(chan struct{}, 1),
+	}
+	b.effective.Store(int64(interval))
+	b.mode.Store(PollFixed)
+	return b
+}
+
+// Run 폴링 주기마다 상태를 조회 (반환하지 않음 - 고루틴으로 실행)
+func (b *StateBroker) Run() {
+	b.refresh(0)
+	interval := b.nextInterval(b.interval)
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		fresh := interval / 2
+		select {
+		case <-timer.C:
+		case <-b.wake:
+			// 명령/새 클라이언트 - 방금 읽은 상태가 아니면 바로 다시 조회
+			timer.Stop()
+			fresh = b.policy.Min / 2
+		}
+		// 직전에 maxAge 요청으로 조회했으면 그 결과를 이번 주기로 사용
+		b.refresh(fresh)
+		interval = b.nextInterval(interval)
+		timer.Reset(interval)
+	}
+}
+
+// SetKinematics 속도/가속도 추정기 설정 (Run 전에 호출)
+func (b *StateBroker) SetKinematics(k *KinematicsEstimator) {
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+	b.kinematics = k
+}
+
+// SetWatchdog 상태 데이터 감시기 설정 (Run 전에 호출)
+func (b *StateBroker) SetWatchdog(w *StaleWatchdog) {
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+	b.watchdog = w
+}
+
+// SetPollPolicy 적응형 폴링 켜기 (Run 전에 호출)
+func (b *StateBroker) SetPollPolicy(policy PollPolicy) {
+	if policy.Min <= 0 || policy.Min > b.interval {
+		policy.Min = b.interval
+	}
+	if policy.Max < b.interval {
+		policy.Max = b.interval
+	}
+	b.policy = &policy
+}
+
+// Interval 기본 폴링 주기
+func (b *StateBroker) Interval() time.Duration {
+	return b.interval
+}
+
+// EffectiveInterval 현재 폴링 주기와 그 이유 (PollFast, PollWatched, PollIdle, PollFixed)
+func (b *StateBroker) EffectiveInterval() (time.Duration, string) {
+	return time.Duration(b.effective.Load()), b.mode.Load().(string)
+}
+
+// Boost 명령을 보냈음 - POLL_BOOST_DURATION 동안 빠른 주기 (유휴 중이면 바로 조회)
+func (b *StateBroker) Boost() {
+	if b.policy == nil {
+		return
+	}
+	b.boostUntil.Store(time.Now().Add(POLL_BOOST_DURATION).UnixNano())
+	if time.Duration(b.effective.Load()) > b.policy.Min {
+		b.signalWake()
+	}
+}
+
+// Watch 상태를 계속 보는 클라이언트 등록 (WebSocket, SSE) - 해제 함수를 반드시 호출해야 합니다.
+func (b *StateBroker) Watch() func() {
+	if b.watchers.Add(1) == 1 {
+		b.wakeIfIdle()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { b.watchers.Add(-1) }) }
+}
+
+// wakeIfIdle 유휴 주기로 대기 중이면 깨움
+func (b *StateBroker) wakeIfIdle() {
+	if b.policy != nil && b.mode.Load().(string) == PollIdle {
+		b.signalWake()
+	}
+}
+
+// signalWake Run 깨우기 (이미 신호가 있으면 무시)
+func (b *StateBroker) signalWake() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextInterval 마지막 조회 결과와 수요로 다음 폴링 주기 결정
+func (b *StateBroker) nextInterval(prev time.Duration) time.Duration {
+	interval, mode := b.interval, PollFixed
+	if p := b.policy; p != nil {
+		now := time.Now()
+		switch {
+		case now.UnixNano() < b.boostUntil.Load() || b.moving():
+			interval, mode = p.Min, PollFast
+		case b.watchers.Load() > 0 || now.Sub(time.Unix(0, b.demandAt.Load())) < POLL_DEMAND_WINDOW || (p.Watching != nil && p.Watching()):
+			interval, mode = b.interval, PollWatched
+		default:
+			interval, mode = min(max(prev*2, b.interval), p.Max), PollIdle
+		}
+	}
+
+	if prevMode := b.mode.Swap(mode); prevMode != mode {
+		logDebug("폴링 주기 변경: %v (%s) → %v (%s)", time.Duration(b.effective.Load()), prevMode, interval, mode)
+	}
+	b.effective.Store(int64(interval))
+	return interval
+}
+
+// moving 마지막 상태에서 움직이는 축이 있는지
+func (b *StateBroker) moving() bool {
+	snap := b.Latest()
+	return snap.State != nil && snap.State.Kinematics != nil && snap.State.Kinematics.Moving
+}
+
+// Latest 마지막 스냅샷 (컨트롤러에 요청하지 않음)
+func (b *StateBroker) Latest() StateSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.latest
+}
+
+// Get 캐시된 상태 조회 - maxAge > 0이면 그보다 오래된 상태는 다시 조회
+func (b *StateBroker) Get(maxAge time.Duration) StateSnapshot {
+	b.demandAt.Store(time.Now().UnixNano())
+	b.wakeIfIdle()
+
+	snap := b.Latest()
+	if snap.State != nil {
+		if maxAge <= 0 {
+			return snap
+		}
+		if snap.Err == nil && snap.Age() <= maxAge {
+			return snap
+		}
+	}
+	return b.refresh(maxAge)
+}
+
+// refresh 컨트롤러에서 상태를 다시 읽어 게시
+// 잠금을 기다리는 동안 다른 호출이 fresh보다 새로운 상태를 읽었으면 그 결과를 사용합니다.
+func (b *StateBroker) refresh(fresh time.Duration) StateSnapshot {
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+
+	if snap := b.Latest(); fresh > 0 && snap.State != nil && snap.Err == nil && snap.Age() <= fresh {
+		return snap
+	}
+
+	state, err := b.ctrl.GetRobotData()
+	now := time.Now()
+	if err == nil && b.kinematics != nil {
+		state.Kinematics = b.kinematics.Update(state, now)
+	}
+	if err == nil && b.watchdog != nil {
+		b.watchdog.Check(state, now, time.Duration(b.effective.Load()))
+	}
+
+	b.mu.Lock()
+	snap := b.latest
+	snap.Err = err
+	if err == nil {
+		snap.Seq++
+		state.Meta.Timestamp = now.Format(time.RFC3339Nano)
+		state.Meta.Source = STATE_SOURCE
+		state.Meta.Seq = snap.Seq
+		snap.State = state
+		snap.At = now
+	}
+	b.latest = snap
+	for _, ch := range b.subs {
+		publishSnapshot(ch, snap)
+	}
+	b.mu.Unlock()
+
+	return snap
+}
+
+// Subscribe 조회 결과 구독 - 해제 함수를 반드시 호출해야 합니다.
+func (b *StateBroker) Subscribe() (<-chan StateSnapshot, func()) {
+	ch := make(chan StateSnapshot, 1)
+
+	b.mu.Lock()
+	id := b.nextSub
+	b.nextSub++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}
+}
+
+// publishSnapshot 구독 채널에 전송 - 이전 값을 아직 읽지 않았으면 최신 값으로 교체
+func publishSnapshot(ch chan StateSnapshot, snap StateSnapshot) {
+	select {
+	case ch <- snap:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- snap:
+	default:
+	}
+}
+
+// ============================================================================
+// 명령 후 빠른 폴링 (Poll Boost)
+// ============================================================================
+
+// PollBoostController 명령을 보낼 때마다 상태 브로커를 빠른 주기로 바꾸는 Controller 데코레이터
+// 연속 JOG 세션은 반복 전송마다 Boost하므로 세션이 끝날 때까지 빠른 주기를 유지합니다.
+type PollBoostController struct {
+	inner  Controller
+	broker *StateBroker
+}
+
+// NewPollBoostController PollBoostController 생성
+func NewPollBoostController(inner Controller, broker *StateBroker) *PollBoostController {
+	return &PollBoostController{inner: inner, broker: broker}
+}
+
+// SendJogCommand 전송 후 Boost
+func (c *PollBoostController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	defer c.broker.Boost()
+	return c.inner.SendJogCommand(cmd)
+}
+
+// SetJogMode 전송 후 Boost
+func (c *PollBoostController) SetJogMode(mode string) (*types.JogResponse, error) {
+	defer c.broker.Boost()
+	return c.inner.SetJogMode(mode)
+}
+
+// SetAxis 전송 후 Boost
+func (c *PollBoostController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	defer c.broker.Boost()
+	return c.inner.SetAxis(axis, robot)
+}
+
+// DisableJog 전송 후 Boost (정지할 때까지 감속을 봄)
+func (c *PollBoostController) DisableJog() (*types.JogResponse, error) {
+	defer c.broker.Boost()
+	return c.inner.DisableJog()
+}
+
+// GetRobotData 그대로 전달
+func (c *PollBoostController) GetRobotData() (*types.JogState, error) {
+	return c.inner.GetRobotData()
+}
+
+// ConnectionStatus 그대로 전달
+func (c *PollBoostController) ConnectionStatus() types.ConnectionStatus {
+	return c.inner.ConnectionStatus()
+}
@@ -0,0 +1,236 @@
+// ============================================================================
+// internal/robot/connection.go - 컨트롤러 연결 상태 추적
+// ============================================================================
+// 상태 폴링과 명령 전송의 성공/실패로 컨트롤러 연결 상태를 판정합니다.
+// UI가 "로봇 대기 중"과 "컨트롤러 연결 끊김"을 구분할 수 있도록
+// JogStatus.IsConnected와 /api/connection으로 노출됩니다.
+//
+// 상태 전이 (히스테리시스):
+// - connecting   → connected    : 첫 성공
+// - connected    → degraded     : 연속 실패 FailuresToDegraded회
+// - degraded     → connected    : 성공 1회
+// - *            → disconnected : 연속 실패 FailuresToDisconnect회
+// - disconnected → connecting   : 성공 1회 (재연결 시도 중)
+// - connecting   → connected    : 연속 성공 SuccessesToRecover회 (재연결 시)
+//
+// disconnected 상태에서는 실패할 때마다 재시도 대기 시간이 두 배로 늘어나며
+// (BackoffMin ~ BackoffMax), 대기 중에는 상태 폴링을 보내지 않습니다.
+// ============================================================================
+
+package robot
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// ConnectionState 컨트롤러 연결 상태
+type ConnectionState string
+
+// 연결 상태 상수
+const (
+	ConnStateConnecting   ConnectionState = "connecting"
+	ConnStateConnected    ConnectionState = "connected"
+	ConnStateDegraded     ConnectionState = "degraded"
+	ConnStateDisconnected ConnectionState = "disconnected"
+)
+
+// 연결 추적 기본값
+const (
+	DEFAULT_FAILURES_TO_DEGRADED   = 1
+	DEFAULT_FAILURES_TO_DISCONNECT = 3
+	DEFAULT_SUCCESSES_TO_RECOVER   = 2
+	DEFAULT_BACKOFF_MIN            = 500 * time.Millisecond
+	DEFAULT_BACKOFF_MAX            = 30 * time.Second
+)
+
+// ErrReconnectBackoff 재연결 대기 중이라 요청을 보내지 않음
+var ErrReconnectBackoff = errors.New("컨트롤러 재연결 대기 중")
+
+// ============================================================================
+// 연결 추적기 (Connection Tracker)
+// ============================================================================
+
+// ConnectionOptions 연결 상태 판정 옵션
+type ConnectionOptions struct {
+	FailuresToDegraded   int           // connected → degraded 연속 실패 횟수
+	FailuresToDisconnect int           // → disconnected 연속 실패 횟수
+	SuccessesToRecover   int           // 재연결 시 connected 복귀 연속 성공 횟수
+	BackoffMin           time.Duration // 재연결 최소 대기 시간
+	BackoffMax           time.Duration // 재연결 최대 대기 시간
+}
+
+// ConnectionTracker 컨트롤러 연결 상태 추적기
+type ConnectionTracker struct {
+	mu   sync.Mutex
+	opts ConnectionOptions
+
+	state                ConnectionState
+	since                time.Time
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	lastSuccess          time.Time
+	lastError            time.Time
+	lastErrorMessage     string
+	backoff              time.Duration
+	nextAttempt          time.Time
+}
+
+// NewConnectionTracker 연결 추적기 생성 (초기 상태 connecting)
+func NewConnectionTracker(opts ConnectionOptions) *ConnectionTracker {
+	if opts.FailuresToDegraded <= 0 {
+		opts.FailuresToDegraded = DEFAULT_FAILURES_TO_DEGRADED
+	}
+	if opts.FailuresToDisconnect <= 0 {
+		opts.FailuresToDisconnect = DEFAULT_FAILURES_TO_DISCONNECT
+	}
+	if opts.FailuresToDisconnect < opts.FailuresToDegraded {
+		opts.FailuresToDisconnect = opts.FailuresToDegraded
+	}
+	if opts.SuccessesToRecover <= 0 {
+		opts.SuccessesToRecover = DEFAULT_SUCCESSES_TO_RECOVER
+	}
+	if opts.BackoffMin <= 0 {
+		opts.BackoffMin = DEFAULT_BACKOFF_MIN
+	}
+	if opts.BackoffMax < opts.BackoffMin {
+		opts.BackoffMax = DEFAULT_BACKOFF_MAX
+	}
+
+	return &ConnectionTracker{
+		opts:  opts,
+		state: ConnStateConnecting,
+		since: time.Now(),
+	}
+}
+
+// RecordSuccess 컨트롤러가 응답한 요청 기록
+func (t *ConnectionTracker) RecordSuccess() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.lastSuccess = now
+	t.consecutiveFailures = 0
+	t.consecutiveSuccesses++
+	t.backoff = 0
+	t.nextAttempt = time.Time{}
+
+	switch t.state {
+	case ConnStateDisconnected:
+		t.setState(ConnStateConnecting, now)
+		if t.opts.SuccessesToRecover <= 1 {
+			t.setState(ConnStateConnected, now)
+		}
+	case ConnStateConnecting:
+		// 최초 연결은 즉시, 재연결은 연속 성공 횟수를 채워야 connected
+		if t.lastError.IsZero() || t.consecutiveSuccesses >= t.opts.SuccessesToRecover {
+			t.setState(ConnStateConnected, now)
+		}
+	case ConnStateDegraded:
+		t.setState(ConnStateConnected, now)
+	}
+}
+
+// RecordFailure 컨트롤러에 도달하지 못한 요청 기록
+func (t *ConnectionTracker) RecordFailure(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.lastError = now
+	if err != nil {
+		t.lastErrorMessage = err.Error()
+	}
+	t.consecutiveSuccesses = 0
+	t.consecutiveFailures++
+
+	switch {
+	case t.consecutiveFailures >= t.opts.FailuresToDisconnect:
+		t.setState(ConnStateDisconnected, now)
+	case t.state == ConnStateConnected && t.consecutiveFailures >= t.opts.FailuresToDegraded:
+		t.setState(ConnStateDegraded, now)
+	}
+
+	// 연결이 끊긴 동안에는 재시도 간격을 지수적으로 늘림
+	if t.state == ConnStateDisconnected {
+		if t.backoff == 0 {
+			t.backoff = t.opts.BackoffMin
+		} else {
+			t.backoff *= 2
+			if t.backoff > t.opts.BackoffMax {
+				t.backoff = t.opts.BackoffMax
+			}
+		}
+		t.nextAttempt = now.Add(t.backoff)
+	}
+}
+
+// setState 상태 변경 및 전이 로그 (호출자가 잠금 보유)
+func (t *ConnectionTracker) setState(state ConnectionState, now time.Time) {
+	if t.state == state {
+		return
+	}
+	if state == ConnStateConnected || state == ConnStateConnecting {
+		logInfo("🔌 컨트롤러 연결 상태: %s → %s", t.state, state)
+	} else {
+		logInfo("🔌 컨트롤러 연결 상태: %s → %s (연속 실패 %d회: %s)", t.state, state, t.consecutiveFailures, t.lastErrorMessage)
+	}
+	t.state = state
+	t.since = now
+}
+
+// State 현재 연결 상태
+func (t *ConnectionTracker) State() ConnectionState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// IsConnected 컨트롤러와 통신 가능한 상태인지 (connected 또는 degraded)
+func (t *ConnectionTracker) IsConnected() bool {
+	state := t.State()
+	return state == ConnStateConnected || state == ConnStateDegraded
+}
+
+// InBackoff 재연결 대기 중인지 확인
+func (t *ConnectionTracker) InBackoff(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state == ConnStateDisconnected && now.Before(t.nextAttempt)
+}
+
+// Status 연결 상태 스냅샷 (API 응답용)
+func (t *ConnectionTracker) Status() types.ConnectionStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status := types.ConnectionStatus{
+		State:               string(t.state),
+		Connected:           t.state == ConnStateConnected || t.state == ConnStateDegraded,
+		Since:               formatTime(t.since),
+		LastSuccess:         formatTime(t.lastSuccess),
+		LastError:           formatTime(t.lastError),
+		LastErrorMessage:    t.lastErrorMessage,
+		ConsecutiveFailures: t.consecutiveFailures,
+	}
+	if wait := time.Until(t.nextAttempt); t.state == ConnStateDisconnected && wait > 0 {
+		status.RetryInMs = wait.Milliseconds()
+	}
+	return status
+}
+
+// formatTime 시각을 ISO 8601 문자열로 변환 (zero면 빈 문자열)
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,380 @@
+// ============================================================================
+// internal/robot/controller.go - 로봇 컨트롤러 인터페이스 및 HTTP 구현
+// ============================================================================
+// 핸들러와 모니터가 로봇 컨트롤러와 통신하는 경로를 추상화합니다.
+// main.go에서 실제 컨트롤러, 시뮬레이터, 테스트용 가짜 구현을
+// 주입할 수 있도록 Controller 인터페이스를 정의합니다.
+//
+// 주요 기능:
+// - Controller 인터페이스 정의
+// - /wrtpdb, jogrefresh.asp 프로토콜을 사용하는 HTTPController 구현
+// - 컨트롤러별 주소, HTTP 클라이언트, 옵션 관리
+// ============================================================================
+
+package robot
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 컨트롤러 인터페이스 (Controller Interface)
+// ============================================================================
+
+// Controller 로봇 컨트롤러와의 통신 인터페이스
+type Controller interface {
+	// SendJogCommand JOG 명령 전송 (Dir "stop"은 조깅 중단)
+	SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error)
+	// SetJogMode JOG 모드 변경 ("computer", "joint", "world", "tool", "free")
+	SetJogMode(mode string) (*types.JogResponse, error)
+	// SetAxis 축 및 로봇 선택
+	SetAxis(axis int, robot int) (*types.JogResponse, error)
+	// DisableJog JOG 비활성화 (PID 215 = 0, SetJogMode로 다시 활성화)
+	DisableJog() (*types.JogResponse, error)
+	// GetRobotData 로봇의 현재 상태 조회
+	GetRobotData() (*types.JogState, error)
+	// ConnectionStatus 컨트롤러 연결 상태 조회
+	ConnectionStatus() types.ConnectionStatus
+}
+
+// ============================================================================
+// HTTP 컨트롤러 (HTTP Controller)
+// ============================================================================
+
+// 기본 HTTP 타임아웃
+const DEFAULT_CONTROLLER_TIMEOUT = 5 * time.Second
+
+// ControllerOptions HTTPController 생성 옵션
+type ControllerOptions struct {
+	Timeout        time.Duration // 요청 타임아웃 (client가 nil일 때만 사용)
+	RedirectPath   string        // /wrtpdb 전송 후 리다이렉트 경로
+	FollowRedirect bool          // 리다이렉트된 결과 페이지 본문까지 오류 확인
+	Connection     ConnectionOptions
+	Model          *Model         // 축/모드/PID 정의 (nil이면 내장 6축 모델)
+	Layout         *PayloadLayout // jogrefresh.asp 응답 레이아웃 (nil이면 내장 기본 레이아웃)
+}
+
+// HTTPController 실제 컨트롤러의 웹 인터페이스를 사용하는 Controller 구현
+type HTTPController struct {
+	baseURL       string
+	commandURL    string
+	dataURL       string
+	client        *http.Client // 상태 조회 및 결과 페이지 조회용
+	commandClient *http.Client // /wrtpdb 전송용 (리다이렉트를 따르지 않음)
+	opts          ControllerOptions
+	conn          *ConnectionTracker
+}
+
+// NewHTTPController HTTPController 생성
+// address는 "192.168.0.1" 또는 "http://host:port" 형식을 허용합니다.
+// client가 nil이면 연결 풀링이 설정된 기본 클라이언트를 생성합니다.
+func NewHTTPController(address string, client *http.Client, opts ControllerOptions) *HTTPController {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DEFAULT_CONTROLLER_TIMEOUT
+	}
+	if opts.RedirectPath == "" {
+		opts.RedirectPath = ROBOT_REDIRECT
+	}
+	if opts.Model == nil {
+		opts.Model = DefaultModel()
+	}
+	if opts.Layout == nil {
+		opts.Layout = DefaultPayloadLayout()
+	}
+	if client == nil {
+		client = newDefaultHTTPClient(opts.Timeout)
+	}
+
+	// 리다이렉트 대상을 직접 해석하기 위해 명령 전송용 클라이언트는 자동 추적을 끔
+	commandClient := *client
+	commandClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	baseURL := normalizeBaseURL(address)
+	return &HTTPController{
+		baseURL:       baseURL,
+		commandURL:    baseURL + ROBOT_COMMAND_PATH,
+		dataURL:       baseURL + ROBOT_DATA_PATH,
+		client:        client,
+		commandClient: &commandClient,
+		opts:          opts,
+		conn:          NewConnectionTracker(opts.Connection),
+	}
+}
+
+// newDefaultHTTPClient 연결 풀링이 설정된 HTTP 클라이언트 생성
+func newDefaultHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     30 * time.Second,
+		},
+	}
+}
+
+// normalizeBaseURL 주소에 스킴을 붙이고 끝의 슬래시 제거
+func normalizeBaseURL(address string) string {
+	if address == "" {
+		address = DEFAULT_ROBOT_ADDRESS
+	}
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return strings.TrimRight(address, "/")
+}
+
+// BaseURL 컨트롤러 기본 URL 반환
+func (c *HTTPController) BaseURL() string {
+	return c.baseURL
+}
+
+// ConnectionStatus 컨트롤러 연결 상태 조회
+func (c *HTTPController) ConnectionStatus() types.ConnectionStatus {
+	return c.conn.Status()
+}
+
+// recordOutcome 요청 결과를 연결 추적기에 반영
+// 컨트롤러가 정상적으로 응답했다면 명령이 거부되었더라도 연결은 살아 있는 것으로 봅니다.
+func (c *HTTPController) recordOutcome(cmdErr *CommandError) {
+	if cmdErr != nil {
+		switch {
+		case cmdErr.Code == types.ErrCodeUnreachable, cmdErr.Code == types.ErrCodeTimeout:
+			c.conn.RecordFailure(cmdErr)
+			return
+		case cmdErr.Code == types.ErrCodeHTTPStatus && cmdErr.StatusCode >= 500:
+			c.conn.RecordFailure(cmdErr)
+			return
+		}
+	}
+	c.conn.RecordSuccess()
+}
+
+// newForm 공통 필드(nPID, Redirect)가 채워진 명령 폼 생성
+func (c *HTTPController) newForm() url.Values {
+	form := url.Values{}
+	form.Set("nPID", "2")
+	form.Set("Redirect", c.opts.RedirectPath)
+	return form
+}
+
+// sendRobotCommand 로봇에 명령 전송 후 응답(상태 코드, 리다이렉트, 본문)을 검사
+// 결과 로그에는 fields(axis, mode 등)와 traceID, 걸린 시간(latency_ms)이 붙습니다.
+func (c *HTTPController) sendRobotCommand(form url.Values, successMsg string, traceID string, fields logging.Fields) (*types.JogResponse, error) {
+	response := &types.JogResponse{
+		Command:   form.Encode(),
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+	}
+	if fields == nil {
+		fields = logging.Fields{}
+	}
+
+	start := time.Now()
+	resp, err := c.commandClient.PostForm(c.commandURL, form)
+	if err != nil {
+		cmdErr := classifyTransportError(err)
+		c.recordOutcome(cmdErr)
+		fields["latency_ms"] = logging.Millis(time.Since(start))
+		return failedResponse(response, cmdErr, traceID, fields)
+	}
+	defer resp.Body.Close()
+
+	cmdErr := c.interpretCommandResponse(resp)
+	c.recordOutcome(cmdErr)
+	fields["latency_ms"] = logging.Millis(time.Since(start))
+	if cmdErr != nil {
+		return failedResponse(response, cmdErr, traceID, fields)
+	}
+
+	response.Success = true
+	response.Message = successMsg
+
+	// 성공 메시지 로그
+	logFields(types.LogLevelInfo, traceID, fields, "%s", successMsg)
+
+	return response, nil
+}
+
+// failedResponse 실패 응답 채우기 및 로그
+func failedResponse(response *types.JogResponse, cmdErr *CommandError, traceID string, fields logging.Fields) (*types.JogResponse, error) {
+	response.Success = false
+	response.Message = cmdErr.Message
+	if cmdErr.Err != nil {
+		response.Message += ": " + cmdErr.Err.Error()
+	}
+	response.ErrorCode = cmdErr.Code
+	fields["error_code"] = cmdErr.Code
+	logFields(types.LogLevelInfo, traceID, fields, "명령 실패 [%s]: %s", cmdErr.Code, response.Message)
+	return response, cmdErr
+}
+
+// invalidCommandResponse 명령 생성 실패 응답
+func invalidCommandResponse(message string, err error) (*types.JogResponse, error) {
+	return &types.JogResponse{
+		Success:   false,
+		Message:   message,
+		Command:   "",
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		ErrorCode: types.ErrCodeInvalidCommand,
+	}, &CommandError{Code: types.ErrCodeInvalidCommand, Message: message, Err: err}
+}
+
+// SendJogCommand JOG 명령을 로봇에 전송
+func (c *HTTPController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	// 조깅 중단 명령 처리
+	if cmd.Dir == "stop" {
+		logFields(types.LogLevelInfo, cmd.Meta.TraceID, nil, "JOG 중단 명령 수신")
+
+		// 중단 명령을 로봇 프로토콜로 변환
+		form, err := buildJogCommand(c.opts.Model, cmd, c.opts.RedirectPath)
+		if err != nil {
+			return invalidCommandResponse("중단 명령 생성 실패: "+err.Error(), err)
+		}
+
+		// 로봇에 중단 명령 전송
+		response, err := c.sendRobotCommand(form, "JOG 중단 명령 전송 완료", cmd.Meta.TraceID, logging.Fields{"dir": cmd.Dir, "axis": cmd.Axis})
+		if err != nil {
+			return response, err
+		}
+
+		logFields(types.LogLevelDebug, cmd.Meta.TraceID, nil, "전송된 중단 명령: %s", response.Command)
+		return response, nil
+	}
+
+	// 기본값 설정
+	if cmd.Mode == "" {
+		cmd.Mode = AxesJoint
+	}
+	if cmd.Step == 0 {
+		cmd.Step = 1.0 // 기본 스텝
+	}
+
+	// 명령 수신 로그
+	logFields(types.LogLevelInfo, cmd.Meta.TraceID, jogFields(cmd), "JOG 명령 수신: 모드=%s, 축=%s, 방향=%s, 스텝=%.3f", cmd.Mode, cmd.Axis, cmd.Dir, cmd.Step)
+
+	// JOG 명령을 로봇 프로토콜로 변환
+	form, err := buildJogCommand(c.opts.Model, cmd, c.opts.RedirectPath)
+	if err != nil {
+		return invalidCommandResponse("명령 생성 실패: "+err.Error(), err)
+	}
+
+	// 로봇에 명령 전송
+	successMsg := fmt.Sprintf("JOG 명령 성공: %s %s %s %.3f", cmd.Mode, cmd.Axis, cmd.Dir, cmd.Step)
+	response, err := c.sendRobotCommand(form, successMsg, cmd.Meta.TraceID, jogFields(cmd))
+	if err != nil {
+		return response, err
+	}
+
+	// 명령 전송 로그
+	logFields(types.LogLevelDebug, cmd.Meta.TraceID, nil, "전송된 명령: %s", response.Command)
+
+	return response, nil
+}
+
+// jogFields JOG 명령 로그 필드
+func jogFields(cmd types.JogCommand) logging.Fields {
+	return logging.Fields{"axis": cmd.Axis, "dir": cmd.Dir, "step": cmd.Step, "mode": cmd.Mode}
+}
+
+// SetJogMode 로봇 JOG 모드 변경
+func (c *HTTPController) SetJogMode(mode string) (*types.JogResponse, error) {
+	config, exists := c.opts.Model.modeMap[mode]
+	if !exists {
+		return invalidCommandResponse("지원하지 않는 모드: "+mode, fmt.Errorf("unsupported mode: %s", mode))
+	}
+
+	form := c.newForm()
+	form.Set("PID1", fmt.Sprintf("%s,%s,0,0", PID_JOG_ENABLE, config.Enable))
+	form.Set("PVal1", config.Enable)
+	form.Set("PID2", fmt.Sprintf("%s,%s,0,0", PID_JOG_MODE, config.JogMode))
+	form.Set("PVal2", config.JogMode)
+
+	// 모드 변경 로그
+	logFields(types.LogLevelInfo, "", logging.Fields{"mode": mode}, "JOG 모드 변경: %s", mode)
+
+	// 로봇에 명령 전송
+	successMsg := fmt.Sprintf("JOG 모드 변경 성공: %s", mode)
+	return c.sendRobotCommand(form, successMsg, "", logging.Fields{"mode": mode})
+}
+
+// SetAxis 로봇 축 선택
+func (c *HTTPController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	form := c.newForm()
+
+	// 축 선택 PID 설정 (원본 jogscripts.asp 참고)
+	form.Set("PID1", fmt.Sprintf("%s,0,0,0", PID_AXIS_SELECT))
+	form.Set("PVal1", fmt.Sprintf("%d", axis))
+	form.Set("PID2", fmt.Sprintf("%s,0,0,0", PID_ROBOT_SELECT))
+	form.Set("PVal2", fmt.Sprintf("%d", robot))
+
+	// 축 선택 로그
+	logFields(types.LogLevelInfo, "", logging.Fields{"axis": axis, "robot": robot}, "축 선택: 축=%d, 로봇=%d", axis, robot)
+
+	// 로봇에 명령 전송
+	successMsg := fmt.Sprintf("축 선택 성공: 축=%d, 로봇=%d", axis, robot)
+	return c.sendRobotCommand(form, successMsg, "", logging.Fields{"axis": axis, "robot": robot})
+}
+
+// DisableJog JOG 비활성화 (PID 215 = 0)
+func (c *HTTPController) DisableJog() (*types.JogResponse, error) {
+	form := c.newForm()
+	form.Set("nPID", "1")
+	form.Set("PID1", fmt.Sprintf("%s,0,0,0", PID_JOG_ENABLE))
+	form.Set("PVal1", "0")
+
+	logInfo("JOG 비활성화 명령 전송")
+	return c.sendRobotCommand(form, "JOG 비활성화 성공", "", nil)
+}
+
+// GetRobotData 로봇의 모든 데이터 조회 (재연결 대기 중이면 요청하지 않음)
+func (c *HTTPController) GetRobotData() (*types.JogState, error) {
+	if c.conn.InBackoff(time.Now()) {
+		return nil, ErrReconnectBackoff
+	}
+
+	res, err := c.client.Get(c.dataURL)
+	if err != nil {
+		c.conn.RecordFailure(err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err = fmt.Errorf("상태 조회 HTTP 오류: %s", res.Status)
+		c.conn.RecordFailure(err)
+		return nil, err
+	}
+
+	// 응답 내용을 재사용 버퍼로 읽기 (해석한 상태는 버퍼를 참조하지 않음)
+	buf, err := readPayload(res.Body)
+	if err != nil {
+		c.conn.RecordFailure(err)
+		return nil, err
+	}
+	state, err := parseRobotData(buf.Bytes(), c.opts.Model, c.opts.Layout)
+	if err == nil {
+		state.Meta.Fingerprint = payloadFingerprint(buf.Bytes())
+	}
+	releasePayload(buf)
+	if err != nil {
+		c.conn.RecordFailure(err)
+		return nil, err
+	}
+	for _, e := range state.Meta.ParseErrors {
+		logDebug("상태 응답 필드 오류: %s(#%d)=%q: %s", e.Field, e.Index, e.Raw, e.Error)
+	}
+	c.conn.RecordSuccess()
+
+	state.Status.IsConnected = c.conn.IsConnected()
+	state.Status.ConnectionState = string(c.conn.State())
+	return state, nil
+}
@@ -0,0 +1,240 @@
+// ============================================================================
+// internal/robot/detector.go - 상태 이벤트 감지기
+// ============================================================================
+// 상태 브로커가 읽은 연속된 두 JogState를 모든 필드에 걸쳐 비교하고
+// 타입이 있는 이벤트(StateChangeEvent)를 만들어 구독자 콜백으로 전달합니다.
+// 콘솔 위치 출력(MonitorRobotPosition)도 구독자 중 하나입니다.
+//
+// 감지 규칙:
+// - 축 이벤트는 로봇 모델에 정의된 축만 비교 (축 이름은 모델의 첫 번째 별칭)
+// - motion_started: 조회 1회 사이 변화량이 motion_threshold보다 큼
+// - motion_stopped: 변화가 없는 조회가 stop_samples번 이어짐
+// - position_changed: 마지막으로 보고한 위치에서 position_threshold보다 움직임
+// - 상태 필드(모드, 전원, JOG 허용, 오류, 선택 축, 연결, 축 수)는 값이 바뀔 때마다
+// - 첫 상태는 비교 대상이 없으므로 position_changed만 보고
+// ============================================================================
+
+package robot
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 감지 이벤트 종류 (StateChangeEvent.Type)
+const (
+	DetectMotionStarted    = "motion_started"
+	DetectMotionStopped    = "motion_stopped"
+	DetectPositionChanged  = "position_changed"
+	DetectModeChanged      = "mode_changed"
+	DetectPowerChanged     = "power_changed"
+	DetectJogAllowed       = "jog_allowed_changed"
+	DetectErrorRaised      = "error_raised"
+	DetectErrorCleared     = "error_cleared"
+	DetectToolChanged      = "tool_changed"
+	DetectAxisSelected     = "axis_selected"
+	DetectConnectionState  = "connection_state_changed"
+	DetectAxisCountChanged = "axis_count_changed"
+)
+
+// axisMotion 축별 움직임 추적
+type axisMotion struct {
+	moving bool
+	still  int     // 움직임 중 변화가 없었던 연속 조회 수
+	start  float64 // 움직이기 시작한 위치
+}
+
+// EventDetector 연속된 상태를 비교해 이벤트를 만드는 감지기
+type EventDetector struct {
+	cfg  types.DetectorConfig
+	axes []axisRef
+
+	mu       sync.Mutex
+	prev     *types.JogState
+	prevSeq  uint64
+	reported *types.JogState // 마지막 position_changed 상태
+	motion   map[string]*axisMotion
+	subs     map[int]func(types.StateChangeEvent)
+	nextSub  int
+}
+
+// NewEventDetector 모델 축 기준으로 감지기 생성 (model이 nil이면 내장 모델)
+func NewEventDetector(model *Model, cfg types.DetectorConfig) *EventDetector {
+	if model == nil {
+		model = DefaultModel()
+	}
+	if cfg.StopSamples < 1 {
+		cfg.StopSamples = 1
+	}
+
+	d := &EventDetector{
+		cfg:    cfg,
+		axes:   model.axisRefs(),
+		motion: make(map[string]*axisMotion),
+		subs:   make(map[int]func(types.StateChangeEvent)),
+	}
+	for _, axis := range d.axes {
+		d.motion[axis.name] = &axisMotion{}
+	}
+	return d
+}
+
+// Subscribe 이벤트 콜백 등록 - 해제 함수 반환
+// 콜백은 감지 고루틴에서 순서대로 호출되므로 오래 걸리는 작업은 직접 넘겨야 합니다.
+func (d *EventDetector) Subscribe(fn func(types.StateChangeEvent)) func() {
+	d.mu.Lock()
+	id := d.nextSub
+	d.nextSub++
+	d.subs[id] = fn
+	d.mu.Unlock()
+
+	return func() {
+		d.mu.Lock()
+		delete(d.subs, id)
+		d.mu.Unlock()
+	}
+}
+
+// Run 상태 브로커 구독 → 성공한 조회마다 Detect (반환하지 않음 - 고루틴으로 실행)
+func (d *EventDetector) Run(broker *StateBroker) {
+	updates, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for snap := range updates {
+		if snap.Err == ErrReconnectBackoff {
+			logVerbose("좌표 읽기 건너뜀: %v", snap.Err)
+			continue
+		}
+		if snap.Err != nil {
+			logDebug("좌표 읽기 실패: %v", snap.Err)
+			continue
+		}
+		d.Detect(snap.State, snap.Seq, snap.At)
+	}
+}
+
+// Detect 이전 상태와 비교해 이벤트를 만들고 구독자에게 전달 (만든 이벤트 반환)
+// 같은 조회 순번의 상태가 다시 들어오면 무시합니다.
+func (d *EventDetector) Detect(state *types.JogState, seq uint64, at time.Time) []types.StateChangeEvent {
+	d.mu.Lock()
+	if state == nil || (seq != 0 && seq == d.prevSeq) {
+		d.mu.Unlock()
+		return nil
+	}
+
+	base := types.StateChangeEvent{Seq: seq, Time: at.Format(time.RFC3339Nano), State: state}
+	var events []types.StateChangeEvent
+	emit := func(event types.StateChangeEvent) {
+		event.Seq, event.Time, event.State = base.Seq, base.Time, base.State
+		events = append(events, event)
+	}
+
+	if d.prev != nil {
+		d.detectStatus(d.prev, state, emit)
+		d.detectMotion(d.prev, state, emit)
+		if changedBeyond(d.prev.ToolData, state.ToolData, d.cfg.ToolThreshold) {
+			emit(types.StateChangeEvent{Type: DetectToolChanged, From: d.prev.ToolData, To: state.ToolData})
+		}
+	}
+	if d.reported == nil || d.positionChanged(d.reported, state) {
+		emit(types.StateChangeEvent{
+			Type: DetectPositionChanged,
+			To:   map[string][]float64{AxesJoint: state.Joint, AxesCartesian: state.Cartesian},
+		})
+		d.reported = state
+	}
+	d.prev, d.prevSeq = state, seq
+
+	subs := make([]func(types.StateChangeEvent), 0, len(d.subs))
+	for _, fn := range d.subs {
+		subs = append(subs, fn)
+	}
+	d.mu.Unlock()
+
+	for _, event := range events {
+		for _, fn := range subs {
+			fn(event)
+		}
+	}
+	return events
+}
+
+// detectStatus 상태 필드 변화 감지
+func (d *EventDetector) detectStatus(prev, cur *types.JogState, emit func(types.StateChangeEvent)) {
+	p, c := prev.Status, cur.Status
+	if p.JogMode != c.JogMode {
+		emit(types.StateChangeEvent{Type: DetectModeChanged, From: p.JogMode, To: c.JogMode})
+	}
+	if p.PowerState != c.PowerState {
+		emit(types.StateChangeEvent{Type: DetectPowerChanged, From: p.PowerState, To: c.PowerState})
+	}
+	if p.AllowJog != c.AllowJog {
+		emit(types.StateChangeEvent{Type: DetectJogAllowed, From: p.AllowJog, To: c.AllowJog})
+	}
+	if p.ErrorDesc != c.ErrorDesc {
+		if c.ErrorDesc == "" {
+			emit(types.StateChangeEvent{Type: DetectErrorCleared, From: p.ErrorDesc, To: c.ErrorDesc})
+		} else {
+			emit(types.StateChangeEvent{Type: DetectErrorRaised, From: p.ErrorDesc, To: c.ErrorDesc})
+		}
+	}
+	if p.SelectedAxis != c.SelectedAxis {
+		emit(types.StateChangeEvent{Type: DetectAxisSelected, From: p.SelectedAxis, To: c.SelectedAxis})
+	}
+	if p.ConnectionState != c.ConnectionState {
+		emit(types.StateChangeEvent{Type: DetectConnectionState, From: p.ConnectionState, To: c.ConnectionState})
+	}
+	if p.AxisCount != c.AxisCount {
+		emit(types.StateChangeEvent{Type: DetectAxisCountChanged, From: p.AxisCount, To: c.AxisCount})
+	}
+}
+
+// detectMotion 축별 움직임 시작/정지 감지
+func (d *EventDetector) detectMotion(prev, cur *types.JogState, emit func(types.StateChangeEvent)) {
+	for _, axis := range d.axes {
+		before, now := axis.value(prev), axis.value(cur)
+		m := d.motion[axis.name]
+
+		if math.Abs(now-before) > d.cfg.MotionThreshold {
+			m.still = 0
+			if !m.moving {
+				m.moving, m.start = true, before
+				emit(types.StateChangeEvent{Type: DetectMotionStarted, Axis: axis.name, Group: axis.group, From: before, To: now})
+			}
+			continue
+		}
+		if m.moving {
+			m.still++
+			if m.still >= d.cfg.StopSamples {
+				m.moving, m.still = false, 0
+				emit(types.StateChangeEvent{Type: DetectMotionStopped, Axis: axis.name, Group: axis.group, From: m.start, To: now})
+			}
+		}
+	}
+}
+
+// positionChanged 마지막 보고 위치에서 position_threshold보다 움직인 축이 있는지
+func (d *EventDetector) positionChanged(reported, cur *types.JogState) bool {
+	for _, axis := range d.axes {
+		if math.Abs(axis.value(cur)-axis.value(reported)) > d.cfg.PositionThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// changedBeyond 배열 길이가 다르거나 threshold보다 크게 바뀐 값이 있는지
+func changedBeyond(prev, cur []float64, threshold float64) bool {
+	if len(prev) != len(cur) {
+		return true
+	}
+	for i := range prev {
+		if math.Abs(cur[i]-prev[i]) > threshold {
+			return true
+		}
+	}
+	return false
+}
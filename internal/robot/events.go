@@ -0,0 +1,271 @@
+// ============================================================================
+// internal/robot/events.go - 로봇 이벤트 버스 (SSE 피드)
+// ============================================================================
+// 상태 브로커의 조회 결과와 상태 이벤트 감지기(MonitorRobotPosition과 같은
+// 소스), 클라이언트가 보낸 명령을 하나의 이벤트 흐름으로 모읍니다. 이벤트마다 단조 증가하는
+// ID를 붙이고 최근 이벤트를 메모리에 보관해, 프록시 때문에 연결이 끊긴
+// 대시보드가 Last-Event-ID로 놓친 이벤트를 다시 받을 수 있습니다.
+//
+// 이벤트 종류:
+// - state: 성공한 상태 조회마다 (JogState)
+// - mode_changed: JOG 모드 번호가 바뀐 경우
+// - error: 상태 조회 실패, 컨트롤러 error_desc 변경
+// - connection: 컨트롤러 연결 상태 변경
+// - command: JOG/모드/축/세션/전체 정지 명령과 결과
+// - stale: 상태 데이터 감시 결과 변경 (watchdog.go)
+// ============================================================================
+
+package robot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 이벤트 종류 (RobotEvent.Type)
+const (
+	EventState       = "state"
+	EventModeChanged = "mode_changed"
+	EventError       = "error"
+	EventConnection  = "connection"
+	EventCommand     = "command"
+	EventStale       = "stale"
+)
+
+// 이벤트 버스 기본값
+const (
+	DEFAULT_EVENT_BUFFER_SIZE = 256 // 재전송용으로 보관할 최근 이벤트 수
+	EVENT_SUBSCRIBER_BUFFER   = 64  // 구독자 채널 크기 (가득 차면 구독 종료)
+)
+
+// ============================================================================
+// 이벤트 버스 (EventBus)
+// ============================================================================
+
+// EventBus 이벤트 게시/구독과 최근 이벤트 버퍼
+type EventBus struct {
+	mu      sync.Mutex
+	nextID  uint64
+	buffer  []types.RobotEvent // 링 버퍼 (오래된 순서는 start부터)
+	start   int
+	size    int
+	subs    map[int]chan types.RobotEvent
+	nextSub int
+}
+
+// NewEventBus 이벤트 버스 생성 (size <= 0이면 기본 크기)
+func NewEventBus(size int) *EventBus {
+	if size <= 0 {
+		size = DEFAULT_EVENT_BUFFER_SIZE
+	}
+	return &EventBus{
+		buffer: make([]types.RobotEvent, size),
+		subs:   make(map[int]chan types.RobotEvent),
+	}
+}
+
+// Publish 이벤트 게시 - ID와 시각을 붙여 버퍼에 보관하고 구독자에게 전송
+// 채널이 가득 찬 구독자는 구독을 끊습니다 (이벤트를 조용히 건너뛰지 않도록
+// 다시 연결해 Last-Event-ID로 재전송 받게 함).
+func (b *EventBus) Publish(eventType string, data interface{}) types.RobotEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := types.RobotEvent{
+		ID:   b.nextID,
+		Type: eventType,
+		Time: time.Now().Format(time.RFC3339Nano),
+		Data: data,
+	}
+
+	if b.size < len(b.buffer) {
+		b.buffer[(b.start+b.size)%len(b.buffer)] = event
+		b.size++
+	} else {
+		b.buffer[b.start] = event
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for id, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			close(ch)
+			delete(b.subs, id)
+		}
+	}
+	return event
+}
+
+// LastID 마지막으로 게시한 이벤트 ID
+func (b *EventBus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextID
+}
+
+// Subscribe 구독 시작 - lastID 이후의 보관된 이벤트와 구독 채널 반환
+// complete가 false면 lastID 다음 이벤트가 이미 버퍼에서 밀려나 일부를 재전송할 수 없습니다.
+// lastID가 0이면 재전송하지 않습니다. 채널이 닫히면 구독이 끊긴 것이며,
+// 해제 함수는 반드시 호출해야 합니다.
+func (b *EventBus) Subscribe(lastID uint64) (replay []types.RobotEvent, complete bool, events <-chan types.RobotEvent, unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		// 서버 재시작 등으로 lastID가 최신 ID보다 크면 보관된 이벤트를 모두 재전송
+		if lastID > b.nextID {
+			lastID = 0
+			complete = false
+		}
+		for i := 0; i < b.size; i++ {
+			event := b.buffer[(b.start+i)%len(b.buffer)]
+			if event.ID > lastID {
+				replay = append(replay, event)
+			}
+		}
+		if lastID > 0 && b.size > 0 && b.buffer[b.start].ID > lastID+1 {
+			complete = false
+		}
+	}
+
+	ch := make(chan types.RobotEvent, EVENT_SUBSCRIBER_BUFFER)
+	id := b.nextSub
+	b.nextSub++
+	b.subs[id] = ch
+
+	return replay, complete, ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[id]; ok {
+			close(ch)
+			delete(b.subs, id)
+		}
+	}
+}
+
+// ============================================================================
+// 상태 이벤트 (State Events)
+// ============================================================================
+
+// WatchStateEvents 상태 이벤트 게시 (반환하지 않음 - 고루틴으로 실행)
+// state/error(poll)/connection은 상태 브로커에서, mode_changed/error(controller)는
+// 감지기 이벤트에서 만듭니다. 모드 표시명은 model로 변환합니다 (nil이면 내장 모델).
+func WatchStateEvents(broker *StateBroker, detector *EventDetector, bus *EventBus, model *Model) {
+	if model == nil {
+		model = DefaultModel()
+	}
+	unsubscribeDetector := detector.Subscribe(func(event types.StateChangeEvent) {
+		switch event.Type {
+		case DetectModeChanged:
+			from, to := event.From.(int), event.To.(int)
+			bus.Publish(EventModeChanged, types.ModeChangedEvent{
+				From:     from,
+				To:       to,
+				FromText: model.modeText(from),
+				ToText:   model.modeText(to),
+			})
+		case DetectErrorRaised:
+			bus.Publish(EventError, types.ErrorEvent{Source: "controller", Message: event.State.Status.ErrorDesc})
+		case DetectErrorCleared:
+			bus.Publish(EventError, types.ErrorEvent{Source: "controller"})
+		}
+	})
+	defer unsubscribeDetector()
+
+	updates, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	pollError := ""
+	connState := broker.ctrl.ConnectionStatus().State
+
+	for snap := range updates {
+		// 연결 상태는 성공/실패와 관계없이 비교
+		if conn := broker.ctrl.ConnectionStatus(); conn.State != connState {
+			bus.Publish(EventConnection, types.ConnectionEvent{From: connState, To: conn.State, Status: conn})
+			connState = conn.State
+		}
+
+		if snap.Err != nil {
+			// 재연결 대기 중에는 조회를 건너뛰므로 새 오류가 아님
+			if snap.Err != ErrReconnectBackoff && snap.Err.Error() != pollError {
+				pollError = snap.Err.Error()
+				bus.Publish(EventError, types.ErrorEvent{Source: "poll", Message: pollError})
+			}
+			continue
+		}
+		if pollError != "" {
+			pollError = ""
+			bus.Publish(EventError, types.ErrorEvent{Source: "poll"})
+		}
+
+		bus.Publish(EventState, snap.State)
+	}
+}
+
+// ============================================================================
+// 명령 이벤트 (Command Events)
+// ============================================================================
+
+// EventController 명령과 결과를 command 이벤트로 게시하는 Controller 데코레이터
+// API 요청 경로에만 사용합니다 (연속 JOG 세션의 반복 전송은 이벤트로 남기지 않음).
+type EventController struct {
+	inner Controller
+	bus   *EventBus
+}
+
+// NewEventController EventController 생성
+func NewEventController(inner Controller, bus *EventBus) *EventController {
+	return &EventController{inner: inner, bus: bus}
+}
+
+// SendJogCommand 전송 후 command 이벤트 게시
+func (c *EventController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	resp, err := c.inner.SendJogCommand(cmd)
+	c.publish("jog", cmd, resp)
+	return resp, err
+}
+
+// SetJogMode 전송 후 command 이벤트 게시
+func (c *EventController) SetJogMode(mode string) (*types.JogResponse, error) {
+	resp, err := c.inner.SetJogMode(mode)
+	c.publish("set_mode", mode, resp)
+	return resp, err
+}
+
+// SetAxis 전송 후 command 이벤트 게시
+func (c *EventController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	resp, err := c.inner.SetAxis(axis, robot)
+	c.publish("set_axis", map[string]int{"axis": axis, "robot": robot}, resp)
+	return resp, err
+}
+
+// DisableJog 전송 후 command 이벤트 게시
+func (c *EventController) DisableJog() (*types.JogResponse, error) {
+	resp, err := c.inner.DisableJog()
+	c.publish("disable_jog", nil, resp)
+	return resp, err
+}
+
+// GetRobotData 그대로 전달
+func (c *EventController) GetRobotData() (*types.JogState, error) {
+	return c.inner.GetRobotData()
+}
+
+// ConnectionStatus 그대로 전달
+func (c *EventController) ConnectionStatus() types.ConnectionStatus {
+	return c.inner.ConnectionStatus()
+}
+
+// publish 명령 결과를 command 이벤트로 게시
+func (c *EventController) publish(action string, request interface{}, resp *types.JogResponse) {
+	event := types.CommandEvent{Action: action, Request: request}
+	if resp != nil {
+		event.Success, event.Message, event.ErrorCode = resp.Success, resp.Message, resp.ErrorCode
+	}
+	c.bus.Publish(EventCommand, event)
+}
@@ -0,0 +1,259 @@
+// ============================================================================
+// internal/robot/history.go - 위치 이력 버퍼
+// ============================================================================
+// 상태 브로커가 읽은 JogState를 시각과 함께 메모리 링 버퍼에 보관합니다.
+// 아차 사고 직후 "1분 전에 로봇이 어디 있었는지"를 확인하기 위한 기능으로,
+// 샘플 수(max_samples)와 보관 기간(retention_sec) 중 먼저 닿는 쪽에서
+// 오래된 샘플을 버립니다.
+//
+// 조회 규칙:
+// - 시간 범위는 [From, To] (0이면 제한 없음)
+// - 축은 모델 축 별칭으로 지정 (없으면 모델의 모든 축)
+// - Bucket > 0이면 벽시계 기준 구간으로 나눠 축별 min/max/avg만 반환
+// ============================================================================
+
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 위치 이력 기본값
+const (
+	DEFAULT_HISTORY_MAX_SAMPLES = 3600
+	DEFAULT_HISTORY_RETENTION   = 10 * time.Minute
+)
+
+// ErrInvalidHistoryQuery 잘못된 이력 조회 조건 (알 수 없는 축, 뒤바뀐 범위)
+var ErrInvalidHistoryQuery = errors.New("잘못된 이력 조회 조건")
+
+// HistoryQuery 이력 조회 조건
+type HistoryQuery struct {
+	From   time.Time     // 이 시각 이후 (0이면 가장 오래된 샘플부터)
+	To     time.Time     // 이 시각 이전 (0이면 최신 샘플까지)
+	Axes   []string      // 모델 축 별칭 (비어 있으면 모든 축)
+	Bucket time.Duration // > 0이면 구간별 min/max/avg
+}
+
+// historySample 보관된 상태 샘플
+type historySample struct {
+	at    time.Time
+	seq   uint64
+	state *types.JogState // 상태 브로커와 공유 (읽기 전용)
+}
+
+// History 위치 이력 링 버퍼
+type History struct {
+	model     *Model
+	retention time.Duration
+
+	mu      sync.Mutex
+	samples []historySample
+	start   int
+	size    int
+}
+
+// NewHistory 위치 이력 버퍼 생성 (0 이하 설정값은 기본값, model이 nil이면 내장 모델)
+func NewHistory(model *Model, cfg types.HistoryConfig) *History {
+	if model == nil {
+		model = DefaultModel()
+	}
+	maxSamples := cfg.MaxSamples
+	if maxSamples <= 0 {
+		maxSamples = DEFAULT_HISTORY_MAX_SAMPLES
+	}
+	retention := time.Duration(cfg.RetentionSec) * time.Second
+	if retention <= 0 {
+		retention = DEFAULT_HISTORY_RETENTION
+	}
+	return &History{
+		model:     model,
+		retention: retention,
+		samples:   make([]historySample, maxSamples),
+	}
+}
+
+// Run 상태 브로커 구독 → 성공한 조회마다 Add (반환하지 않음 - 고루틴으로 실행)
+func (h *History) Run(broker *StateBroker) {
+	updates, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for snap := range updates {
+		if snap.Err == nil && snap.State != nil {
+			h.Add(snap.State, snap.Seq, snap.At)
+		}
+	}
+}
+
+// Add 샘플 추가 - 버퍼가 가득 차면 가장 오래된 샘플을 덮어쓰고 보관 기간이 지난 샘플은 버림
+func (h *History) Add(state *types.JogState, seq uint64, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sample := historySample{at: at, seq: seq, state: state}
+	if h.size < len(h.samples) {
+		h.samples[(h.start+h.size)%len(h.samples)] = sample
+		h.size++
+	} else {
+		h.samples[h.start] = sample
+		h.start = (h.start + 1) % len(h.samples)
+	}
+	h.pruneLocked(at)
+}
+
+// pruneLocked 보관 기간이 지난 샘플 제거 (h.mu 보유 상태에서 호출)
+func (h *History) pruneLocked(now time.Time) {
+	cutoff := now.Add(-h.retention)
+	for h.size > 0 && h.samples[h.start].at.Before(cutoff) {
+		h.samples[h.start] = historySample{}
+		h.start = (h.start + 1) % len(h.samples)
+		h.size--
+	}
+}
+
+// Len 보관 중인 샘플 수
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.size
+}
+
+// Query 시간 범위와 축으로 이력 조회
+func (h *History) Query(q HistoryQuery) (types.HistoryResponse, error) {
+	axes, err := h.resolveAxes(q.Axes)
+	if err != nil {
+		return types.HistoryResponse{}, err
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return types.HistoryResponse{}, fmt.Errorf("%w: to가 from보다 앞섭니다", ErrInvalidHistoryQuery)
+	}
+
+	samples := h.between(q.From, q.To)
+
+	resp := types.HistoryResponse{Axes: make([]string, len(axes))}
+	for i, axis := range axes {
+		resp.Axes[i] = axis.name
+	}
+	from, to := q.From, q.To
+	if from.IsZero() && len(samples) > 0 {
+		from = samples[0].at
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	resp.From, resp.To = from.Format(time.RFC3339Nano), to.Format(time.RFC3339Nano)
+
+	if q.Bucket <= 0 {
+		resp.Samples = make([]types.HistorySample, len(samples))
+		for i, s := range samples {
+			resp.Samples[i] = types.HistorySample{
+				Time:   s.at.Format(time.RFC3339Nano),
+				Seq:    s.seq,
+				Values: sampleValues(s.state, axes),
+			}
+		}
+		return resp, nil
+	}
+
+	resp.DownsampleMs = q.Bucket.Milliseconds()
+	resp.Buckets = bucketSamples(samples, axes, q.Bucket)
+	return resp, nil
+}
+
+// resolveAxes 축 별칭을 모델 축으로 변환 (비어 있으면 모든 축)
+func (h *History) resolveAxes(names []string) ([]axisRef, error) {
+	if len(names) == 0 {
+		return h.model.axisRefs(), nil
+	}
+	axes := make([]axisRef, 0, len(names))
+	for _, name := range names {
+		ref, ok := h.model.findAxisRef(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: 모델 %s에 없는 축: %s", ErrInvalidHistoryQuery, h.model.Name(), name)
+		}
+		axes = append(axes, ref)
+	}
+	return axes, nil
+}
+
+// between 범위 안의 샘플 복사 (오래된 순)
+func (h *History) between(from, to time.Time) []historySample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pruneLocked(time.Now())
+	var samples []historySample
+	for i := 0; i < h.size; i++ {
+		s := h.samples[(h.start+i)%len(h.samples)]
+		if (!from.IsZero() && s.at.Before(from)) || (!to.IsZero() && s.at.After(to)) {
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// sampleValues 상태에서 축 순서대로 값 추출
+func sampleValues(state *types.JogState, axes []axisRef) []float64 {
+	values := make([]float64, len(axes))
+	for i, axis := range axes {
+		values[i] = axis.value(state)
+	}
+	return values
+}
+
+// bucketSamples 벽시계 기준 구간별 축 min/max/avg (샘플이 없는 구간은 생략)
+func bucketSamples(samples []historySample, axes []axisRef, bucket time.Duration) []types.HistoryBucket {
+	var buckets []types.HistoryBucket
+	var cur *types.HistoryBucket
+	var curStart time.Time
+
+	for _, s := range samples {
+		start := s.at.Truncate(bucket)
+		if cur == nil || !start.Equal(curStart) {
+			finishBucket(cur)
+			buckets = append(buckets, types.HistoryBucket{
+				Time: start.Format(time.RFC3339Nano),
+				Min:  filled(len(axes), math.Inf(1)),
+				Max:  filled(len(axes), math.Inf(-1)),
+				Avg:  make([]float64, len(axes)),
+			})
+			cur, curStart = &buckets[len(buckets)-1], start
+		}
+
+		cur.Count++
+		for i, axis := range axes {
+			v := axis.value(s.state)
+			cur.Min[i] = math.Min(cur.Min[i], v)
+			cur.Max[i] = math.Max(cur.Max[i], v)
+			cur.Avg[i] += v // finishBucket에서 평균으로 변환
+		}
+	}
+	finishBucket(cur)
+	return buckets
+}
+
+// finishBucket 합계를 평균으로 변환
+func finishBucket(b *types.HistoryBucket) {
+	if b == nil || b.Count == 0 {
+		return
+	}
+	for i := range b.Avg {
+		b.Avg[i] /= float64(b.Count)
+	}
+}
+
+// filled 같은 값으로 채운 슬라이스
+func filled(n int, v float64) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
@@ -0,0 +1,396 @@
+// ============================================================================
+// internal/robot/jogsession.go - 서버 측 연속 JOG 세션 (데드맨 하트비트)
+// ============================================================================
+// 연속 조깅의 반복 전송을 브라우저가 아닌 서버가 담당합니다.
+// 클라이언트는 세션을 시작한 뒤 하트비트만 보내고, 하트비트가
+// HeartbeatTimeout 안에 도착하지 않으면 서버가 직접 중단 명령
+// (PID1=0,0,0,0)을 전송합니다. 브라우저 탭이 멈추거나 네트워크가
+// 끊겨도 로봇이 계속 움직이지 않도록 하는 안전 기능입니다.
+// ============================================================================
+
+package robot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// JOG 세션 기본값
+const (
+	DEFAULT_JOG_REPEAT_INTERVAL    = 30 * time.Millisecond
+	DEFAULT_JOG_HEARTBEAT_TIMEOUT  = 500 * time.Millisecond
+	DEFAULT_JOG_SESSION_HISTORY    = 16 // 종료된 세션 정보 보관 개수
+	jogSessionEndStopped           = "stopped"
+	jogSessionEndHeartbeatTimeout  = "heartbeat_timeout"
+	jogSessionEndSuperseded        = "superseded"
+	jogSessionEndCommandFailed     = "command_failed"
+	jogSessionEndStopAll           = "stop_all"
+	jogSessionEndConnectionDropped = "connection_closed"
+)
+
+// ErrJogSessionNotFound 세션 ID에 해당하는 활성 세션 없음
+var ErrJogSessionNotFound = errors.New("JOG 세션을 찾을 수 없습니다")
+
+// ============================================================================
+// 세션 관리자 (Session Manager)
+// ============================================================================
+
+// JogSessionOptions JOG 세션 옵션
+type JogSessionOptions struct {
+	RepeatInterval   time.Duration // 컨트롤러로 JOG 명령을 반복 전송하는 간격
+	HeartbeatTimeout time.Duration // 하트비트가 없으면 자동 중단하는 시간
+}
+
+// jogSession 진행 중인 연속 JOG 세션
+type jogSession struct {
+	id            string
+	owner         string
+	cmd           types.JogCommand
+	startedAt     time.Time
+	lastHeartbeat time.Time
+	commandsSent  int
+	endReason     string
+	endedAt       time.Time
+
+	stop chan string // 종료 사유 전달 (버퍼 1)
+	done chan struct{}
+}
+
+// JogSessionManager 서버 측 연속 JOG 세션 관리자
+type JogSessionManager struct {
+	ctrl Controller
+	opts JogSessionOptions
+
+	startMu sync.Mutex // Start 직렬화 (대체, 첫 명령 전송, 등록을 한 번에)
+
+	mu       sync.Mutex
+	active   map[string]*jogSession
+	history  []*jogSession // 최근 종료된 세션
+	abortGen uint64        // AbortAll/StopAll마다 증가 - 진행 중인 Start가 확인
+}
+
+// NewJogSessionManager JOG 세션 관리자 생성
+func NewJogSessionManager(ctrl Controller, opts JogSessionOptions) *JogSessionManager {
+	if opts.RepeatInterval <= 0 {
+		opts.RepeatInterval = DEFAULT_JOG_REPEAT_INTERVAL
+	}
+	if opts.HeartbeatTimeout <= 0 {
+		opts.HeartbeatTimeout = DEFAULT_JOG_HEARTBEAT_TIMEOUT
+	}
+	return &JogSessionManager{
+		ctrl:   ctrl,
+		opts:   opts,
+		active: make(map[string]*jogSession),
+	}
+}
+
+// Start 연속 JOG 세션 시작
+// 첫 번째 명령을 즉시 동기 전송하고, 실패하면 세션을 만들지 않습니다.
+// 한 로봇에는 하나의 세션만 유지되므로 기존 세션은 대체됩니다.
+// 첫 명령을 전송하는 동안 전체 정지가 들어오면 중단 명령을 보내고 세션을
+// 만들지 않습니다.
+func (m *JogSessionManager) Start(cmd types.JogCommand, owner string) (types.JogSessionInfo, *types.JogResponse, error) {
+	if cmd.Dir != "positive" && cmd.Dir != "negative" {
+		resp, err := invalidCommandResponse("연속 JOG 방향은 positive 또는 negative여야 합니다: "+cmd.Dir, nil)
+		return types.JogSessionInfo{}, resp, err
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	m.mu.Lock()
+	gen := m.abortGen
+	m.mu.Unlock()
+
+	m.endAll(jogSessionEndSuperseded)
+
+	resp, err := m.ctrl.SendJogCommand(cmd)
+	if err != nil {
+		return types.JogSessionInfo{}, resp, err
+	}
+
+	now := time.Now()
+	sess := &jogSession{
+		id:            newSessionID(),
+		owner:         owner,
+		cmd:           cmd,
+		startedAt:     now,
+		lastHeartbeat: now,
+		commandsSent:  1,
+		stop:          make(chan string, 1),
+		done:          make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	if m.abortGen != gen {
+		m.mu.Unlock()
+		stop := types.JogCommand{Axis: cmd.Axis, Mode: cmd.Mode, Dir: "stop", Meta: cmd.Meta}
+		if _, err := m.ctrl.SendJogCommand(stop); err != nil {
+			logInfo("❌ 취소된 JOG 세션 중단 명령 전송 실패: %v", err)
+		}
+		resp, err := rejectedResponse(types.ErrCodeStaleCommand, "전체 정지로 연속 JOG 세션 시작 취소")
+		return types.JogSessionInfo{}, resp, err
+	}
+	m.active[sess.id] = sess
+	info := m.infoLocked(sess)
+	m.mu.Unlock()
+
+	logInfo("▶️  연속 JOG 세션 시작: %s (%s %s %s, 소유자=%s)", sess.id, cmd.Mode, cmd.Axis, cmd.Dir, owner)
+	go m.run(sess)
+
+	return info, resp, nil
+}
+
+// Heartbeat 세션 하트비트 갱신
+func (m *JogSessionManager) Heartbeat(id string) (types.JogSessionInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess, ok := m.active[id]
+	if !ok {
+		if ended := m.findEndedLocked(id); ended != nil {
+			return m.infoLocked(ended), ErrJogSessionNotFound
+		}
+		return types.JogSessionInfo{SessionID: id}, ErrJogSessionNotFound
+	}
+	sess.lastHeartbeat = time.Now()
+	return m.infoLocked(sess), nil
+}
+
+// Stop 세션 중단 (중단 명령 전송이 끝날 때까지 대기)
+func (m *JogSessionManager) Stop(id string) (types.JogSessionInfo, error) {
+	m.mu.Lock()
+	sess, ok := m.active[id]
+	m.mu.Unlock()
+
+	if !ok {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if ended := m.findEndedLocked(id); ended != nil {
+			return m.infoLocked(ended), ErrJogSessionNotFound
+		}
+		return types.JogSessionInfo{SessionID: id}, ErrJogSessionNotFound
+	}
+
+	m.signal(sess, jogSessionEndStopped)
+	<-sess.done
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.infoLocked(sess), nil
+}
+
+// StopAll 모든 세션 중단 (중단 명령은 세션마다 전송) - 중단한 세션 수 반환
+func (m *JogSessionManager) StopAll() int {
+	m.mu.Lock()
+	m.abortGen++
+	m.mu.Unlock()
+	return m.endAll(jogSessionEndStopAll)
+}
+
+// AbortAll 모든 세션에 종료를 알리고 기다리지 않음 - 알린 세션 수 반환
+// 전체 정지용: 세션 루프가 컨트롤러 응답을 기다리며 멈춰 있어도 즉시 반환합니다.
+// 각 세션은 마지막 JOG 명령 뒤에 자체 중단 명령을 보내고 종료합니다.
+// 첫 명령을 전송 중인 Start도 세션을 등록하지 않고 중단합니다.
+func (m *JogSessionManager) AbortAll() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.abortGen++
+
+	for _, sess := range m.active {
+		m.signal(sess, jogSessionEndStopAll)
+	}
+	return len(m.active)
+}
+
+// StopOwner 특정 소유자(연결)의 세션 중단 - 중단한 세션 수 반환
+func (m *JogSessionManager) StopOwner(owner string) int {
+	m.mu.Lock()
+	var targets []*jogSession
+	for _, sess := range m.active {
+		if sess.owner == owner {
+			targets = append(targets, sess)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, sess := range targets {
+		m.signal(sess, jogSessionEndConnectionDropped)
+		<-sess.done
+	}
+	return len(targets)
+}
+
+// Active 활성 세션 목록
+func (m *JogSessionManager) Active() []types.JogSessionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]types.JogSessionInfo, 0, len(m.active))
+	for _, sess := range m.active {
+		infos = append(infos, m.infoLocked(sess))
+	}
+	return infos
+}
+
+// ActiveCount 활성 세션 수
+func (m *JogSessionManager) ActiveCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.active)
+}
+
+// Recent 최근 종료된 세션 목록 (최신순)
+func (m *JogSessionManager) Recent() []types.JogSessionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]types.JogSessionInfo, 0, len(m.history))
+	for i := len(m.history) - 1; i >= 0; i-- {
+		infos = append(infos, m.infoLocked(m.history[i]))
+	}
+	return infos
+}
+
+// ============================================================================
+// 세션 루프 (Session Loop)
+// ============================================================================
+
+// run 세션 반복 전송 루프 (세션마다 고루틴 1개)
+func (m *JogSessionManager) run(sess *jogSession) {
+	defer close(sess.done)
+
+	ticker := time.NewTicker(m.opts.RepeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case reason := <-sess.stop:
+			// 대체된 세션도 축/방향이 바뀔 수 있으므로 항상 중단 후 종료
+			m.finish(sess, reason)
+			return
+
+		case now := <-ticker.C:
+			m.mu.Lock()
+			last := sess.lastHeartbeat
+			m.mu.Unlock()
+
+			// 데드맨: 하트비트가 끊기면 서버가 직접 중단
+			if now.Sub(last) > m.opts.HeartbeatTimeout {
+				logInfo("⛔ JOG 세션 %s 하트비트 없음 (%v) - 자동 중단", sess.id, now.Sub(last).Round(time.Millisecond))
+				m.finish(sess, jogSessionEndHeartbeatTimeout)
+				return
+			}
+
+			if _, err := m.ctrl.SendJogCommand(sess.cmd); err != nil {
+				// 전체 정지 장벽으로 거부된 경우 등, 이미 전달된 종료 사유가 있으면 우선
+				reason := jogSessionEndCommandFailed
+				select {
+				case reason = <-sess.stop:
+				default:
+					logInfo("⛔ JOG 세션 %s 명령 실패 - 중단: %v", sess.id, err)
+				}
+				m.finish(sess, reason)
+				return
+			}
+
+			m.mu.Lock()
+			sess.commandsSent++
+			m.mu.Unlock()
+		}
+	}
+}
+
+// finish 중단 명령 전송 후 세션 종료 처리
+func (m *JogSessionManager) finish(sess *jogSession, reason string) {
+	stop := types.JogCommand{Axis: sess.cmd.Axis, Mode: sess.cmd.Mode, Dir: "stop", Meta: sess.cmd.Meta}
+	if _, err := m.ctrl.SendJogCommand(stop); err != nil {
+		logInfo("❌ JOG 세션 %s 중단 명령 전송 실패: %v", sess.id, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess.endReason = reason
+	sess.endedAt = time.Now()
+	delete(m.active, sess.id)
+	m.history = append(m.history, sess)
+	if len(m.history) > DEFAULT_JOG_SESSION_HISTORY {
+		m.history = m.history[len(m.history)-DEFAULT_JOG_SESSION_HISTORY:]
+	}
+
+	logInfo("⏹️  연속 JOG 세션 종료: %s (사유=%s, 전송=%d회)", sess.id, reason, sess.commandsSent)
+}
+
+// signal 세션 루프에 종료 사유 전달 (이미 전달된 경우 무시)
+func (m *JogSessionManager) signal(sess *jogSession, reason string) {
+	select {
+	case sess.stop <- reason:
+	default:
+	}
+}
+
+// endAll 모든 활성 세션 종료 후 대기 - 종료한 세션 수 반환
+func (m *JogSessionManager) endAll(reason string) int {
+	m.mu.Lock()
+	targets := make([]*jogSession, 0, len(m.active))
+	for _, sess := range m.active {
+		targets = append(targets, sess)
+	}
+	m.mu.Unlock()
+
+	for _, sess := range targets {
+		m.signal(sess, reason)
+	}
+	// 대체 시에도 이전 루프가 끝나야 새 세션 명령과 섞이지 않음
+	for _, sess := range targets {
+		<-sess.done
+	}
+	return len(targets)
+}
+
+// findEndedLocked 종료된 세션 조회 (호출자가 잠금 보유)
+func (m *JogSessionManager) findEndedLocked(id string) *jogSession {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if m.history[i].id == id {
+			return m.history[i]
+		}
+	}
+	return nil
+}
+
+// infoLocked 세션 정보 스냅샷 (호출자가 잠금 보유)
+func (m *JogSessionManager) infoLocked(sess *jogSession) types.JogSessionInfo {
+	return types.JogSessionInfo{
+		SessionID:          sess.id,
+		Owner:              sess.owner,
+		Command:            sess.cmd,
+		Active:             sess.endedAt.IsZero(),
+		StartedAt:          formatTime(sess.startedAt),
+		LastHeartbeat:      formatTime(sess.lastHeartbeat),
+		EndedAt:            formatTime(sess.endedAt),
+		EndReason:          sess.endReason,
+		CommandsSent:       sess.commandsSent,
+		HeartbeatTimeoutMs: m.opts.HeartbeatTimeout.Milliseconds(),
+	}
+}
+
+// newSessionID 무작위 세션 ID 생성
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("jog-%d", time.Now().UnixNano())
+	}
+	return "jog-" + hex.EncodeToString(b)
+}
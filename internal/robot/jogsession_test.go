@@ -0,0 +1,153 @@
+// ============================================================================
+// internal/robot/jogsession_test.go - 연속 JOG 세션 테스트
+// ============================================================================
+// 데드맨 하트비트 시간 초과, 세션 대체, 첫 명령 전송 중 전체 정지를
+// 가짜 컨트롤러(queue_test.go)로 확인합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// newTestSessions 짧은 간격의 세션 관리자
+func newTestSessions(ctrl Controller) *JogSessionManager {
+	return NewJogSessionManager(ctrl, JogSessionOptions{
+		RepeatInterval:   2 * time.Millisecond,
+		HeartbeatTimeout: 30 * time.Millisecond,
+	})
+}
+
+func TestJogSessionHeartbeat(t *testing.T) {
+	tests := []struct {
+		name       string
+		heartbeat  bool
+		wantActive bool
+	}{
+		{name: "heartbeat keeps session", heartbeat: true, wantActive: true},
+		{name: "missing heartbeat stops session", heartbeat: false, wantActive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeController{}
+			m := newTestSessions(fake)
+
+			info, _, err := m.Start(types.JogCommand{Axis: "J1", Mode: AxesJoint, Dir: "positive"}, "test")
+			if err != nil {
+				t.Fatalf("Start 실패: %v", err)
+			}
+
+			// 시간 초과의 세 배 동안 대기 (하트비트는 시간 초과보다 자주)
+			for deadline := time.Now().Add(90 * time.Millisecond); time.Now().Before(deadline); {
+				if tt.heartbeat {
+					if _, err := m.Heartbeat(info.SessionID); err != nil {
+						t.Fatalf("Heartbeat 실패: %v", err)
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			if tt.wantActive {
+				if m.ActiveCount() != 1 {
+					t.Fatalf("ActiveCount() = %d, want 1", m.ActiveCount())
+				}
+				if _, err := m.Stop(info.SessionID); err != nil {
+					t.Fatalf("Stop 실패: %v", err)
+				}
+			} else {
+				waitFor(t, "세션 종료", func() bool { return m.ActiveCount() == 0 })
+				recent := m.Recent()
+				if len(recent) != 1 || recent[0].EndReason != jogSessionEndHeartbeatTimeout {
+					t.Fatalf("종료된 세션 = %+v, want 사유 %s", recent, jogSessionEndHeartbeatTimeout)
+				}
+				if _, err := m.Heartbeat(info.SessionID); err != ErrJogSessionNotFound {
+					t.Fatalf("종료 후 Heartbeat 에러 = %v, want ErrJogSessionNotFound", err)
+				}
+			}
+
+			// 어느 쪽이든 마지막 명령은 중단
+			sent := fake.Sent()
+			if last := sent[len(sent)-1]; last != "jog:J1:stop" {
+				t.Fatalf("마지막 명령 = %s, want jog:J1:stop", last)
+			}
+		})
+	}
+}
+
+func TestJogSessionSupersede(t *testing.T) {
+	fake := &fakeController{}
+	m := newTestSessions(fake)
+
+	first, _, err := m.Start(types.JogCommand{Axis: "J1", Mode: AxesJoint, Dir: "positive"}, "a")
+	if err != nil {
+		t.Fatalf("첫 세션 Start 실패: %v", err)
+	}
+	waitFor(t, "반복 전송", func() bool { return len(fake.Sent()) >= 3 })
+
+	second, _, err := m.Start(types.JogCommand{Axis: "J2", Mode: AxesJoint, Dir: "negative"}, "b")
+	if err != nil {
+		t.Fatalf("두 번째 세션 Start 실패: %v", err)
+	}
+
+	active := m.Active()
+	if len(active) != 1 || active[0].SessionID != second.SessionID {
+		t.Fatalf("활성 세션 = %+v, want %s만", active, second.SessionID)
+	}
+	recent := m.Recent()
+	if len(recent) != 1 || recent[0].SessionID != first.SessionID || recent[0].EndReason != jogSessionEndSuperseded {
+		t.Fatalf("종료된 세션 = %+v, want %s (사유 %s)", recent, first.SessionID, jogSessionEndSuperseded)
+	}
+
+	// 이전 세션의 중단 명령이 새 세션의 첫 명령보다 먼저, 이후 J1 명령 없음
+	sent := fake.Sent()
+	stopAt, startAt := -1, -1
+	for i, name := range sent {
+		switch {
+		case name == "jog:J1:stop" && stopAt < 0:
+			stopAt = i
+		case name == "jog:J2:negative" && startAt < 0:
+			startAt = i
+		case name == "jog:J1:positive" && stopAt >= 0:
+			t.Fatalf("중단 후 이전 세션 JOG 전송: %v", sent)
+		}
+	}
+	if stopAt < 0 || startAt < stopAt {
+		t.Fatalf("전송 순서 = %v, want J1 중단 후 J2 시작", sent)
+	}
+
+	m.StopAll()
+}
+
+func TestJogSessionStartAbortedByAllStop(t *testing.T) {
+	fake := &fakeController{hold: make(chan struct{}), enter: make(chan types.JogCommand, 16)}
+	m := newTestSessions(fake)
+
+	started := make(chan error, 1)
+	go func() {
+		_, _, err := m.Start(types.JogCommand{Axis: "J1", Mode: AxesJoint, Dir: "positive"}, "a")
+		started <- err
+	}()
+
+	// 첫 명령 전송 중에 전체 정지
+	<-fake.enter
+	m.AbortAll()
+	close(fake.hold)
+
+	select {
+	case err := <-started:
+		if ErrorCode(err) != types.ErrCodeStaleCommand {
+			t.Fatalf("Start 에러 = %v, want %s", err, types.ErrCodeStaleCommand)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start 시간 초과")
+	}
+	if m.ActiveCount() != 0 {
+		t.Fatalf("ActiveCount() = %d, want 0", m.ActiveCount())
+	}
+	assertSent(t, fake, "jog:J1:positive", "jog:J1:stop")
+}
@@ -0,0 +1,140 @@
+// ============================================================================
+// internal/robot/kinematics.go - 축별 속도/가속도 추정
+// ============================================================================
+// 컨트롤러는 위치만 알려주므로 상태 브로커가 읽은 연속된 두 샘플의 위치
+// 차이를 실제 조회 시각 차이로 나눠 속도를 구하고, 속도 차이로 가속도를
+// 구합니다. 조회 주기가 흔들리거나 조회가 누락되어도 실제 시각을 쓰므로
+// 단위/초 값이 유지됩니다.
+//
+// 추정 규칙:
+// - 1차 저역 통과 필터 (시간 상수 filter_ms, 샘플 간격에 맞춰 계수 계산)
+// - 샘플 간격이 max_gap_ms보다 길면 (연결 끊김 등) 필터를 다시 시작
+// - 다시 시작한 직후 샘플은 Valid=false (속도/가속도 0)
+// - 카르테시안 회전축(단위 deg)은 ±180° 경계를 넘는 변화를 짧은 쪽으로 계산
+// - Moving: 속도 절댓값이 still_velocity를 넘는 축이 하나라도 있음
+// ============================================================================
+
+package robot
+
+import (
+	"math"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// DEFAULT_KINEMATICS_GAP 설정이 없을 때 필터를 다시 시작하는 샘플 간격
+const DEFAULT_KINEMATICS_GAP = 3 * time.Second
+
+// KinematicsEstimator 연속된 상태로 속도/가속도를 추정하는 필터
+// 상태 브로커의 조회 잠금 안에서만 호출되므로 따로 잠그지 않습니다.
+type KinematicsEstimator struct {
+	filter time.Duration
+	maxGap time.Duration
+	still  float64
+	axes   []axisRef
+	wrap   []bool // 축별 ±180° 래핑 여부
+
+	prevAt  time.Time
+	prevPos []float64
+	vel     []float64
+	acc     []float64
+	primed  bool // 속도를 한 번 이상 계산함
+}
+
+// NewKinematicsEstimator 모델 축 기준으로 추정기 생성 (model이 nil이면 내장 모델)
+func NewKinematicsEstimator(model *Model, cfg types.KinematicsConfig) *KinematicsEstimator {
+	if model == nil {
+		model = DefaultModel()
+	}
+	maxGap := time.Duration(cfg.MaxGapMs) * time.Millisecond
+	if maxGap <= 0 {
+		maxGap = DEFAULT_KINEMATICS_GAP
+	}
+
+	k := &KinematicsEstimator{
+		filter: time.Duration(cfg.FilterMs) * time.Millisecond,
+		maxGap: maxGap,
+		still:  cfg.StillVelocity,
+		axes:   model.axisRefs(),
+	}
+	k.wrap = make([]bool, len(k.axes))
+	for i, axis := range k.axes {
+		k.wrap[i] = axis.group == AxesCartesian && model.info.Cartesian[axis.index].Unit == "deg"
+	}
+	return k
+}
+
+// Update 새 샘플로 추정값을 갱신하고 결과 반환 (반환값은 새로 할당 - 공유해도 안전)
+func (k *KinematicsEstimator) Update(state *types.JogState, at time.Time) *types.Kinematics {
+	pos := sampleValues(state, k.axes)
+	dt := at.Sub(k.prevAt)
+
+	var interval float64
+	if !k.prevAt.IsZero() {
+		interval = float64(dt) / float64(time.Millisecond)
+	}
+
+	if k.prevAt.IsZero() || dt <= 0 || dt > k.maxGap {
+		k.reset(pos, at)
+		return k.result(interval, false)
+	}
+
+	seconds := dt.Seconds()
+	alpha := 1.0
+	if k.filter > 0 {
+		alpha = 1 - math.Exp(-seconds/k.filter.Seconds())
+	}
+
+	for i := range k.axes {
+		delta := pos[i] - k.prevPos[i]
+		if k.wrap[i] {
+			delta = math.Remainder(delta, 360)
+		}
+		raw := delta / seconds
+
+		if !k.primed {
+			k.vel[i] = raw
+			continue
+		}
+		vel := k.vel[i] + alpha*(raw-k.vel[i])
+		k.acc[i] += alpha * ((vel-k.vel[i])/seconds - k.acc[i])
+		k.vel[i] = vel
+	}
+	k.primed = true
+	k.prevPos, k.prevAt = pos, at
+	return k.result(interval, true)
+}
+
+// reset 필터를 지우고 pos를 기준 샘플로 사용
+func (k *KinematicsEstimator) reset(pos []float64, at time.Time) {
+	k.prevPos, k.prevAt = pos, at
+	k.vel = make([]float64, len(k.axes))
+	k.acc = make([]float64, len(k.axes))
+	k.primed = false
+}
+
+// result 현재 추정값을 조인트/카르테시안 배열로 나눠 반환
+func (k *KinematicsEstimator) result(interval float64, valid bool) *types.Kinematics {
+	out := &types.Kinematics{
+		JointVelocity:         []float64{},
+		JointAcceleration:     []float64{},
+		CartesianVelocity:     []float64{},
+		CartesianAcceleration: []float64{},
+		IntervalMs:            interval,
+		Valid:                 valid,
+	}
+	for i, axis := range k.axes {
+		if math.Abs(k.vel[i]) > k.still {
+			out.Moving = true
+		}
+		if axis.group == AxesJoint {
+			out.JointVelocity = append(out.JointVelocity, k.vel[i])
+			out.JointAcceleration = append(out.JointAcceleration, k.acc[i])
+		} else {
+			out.CartesianVelocity = append(out.CartesianVelocity, k.vel[i])
+			out.CartesianAcceleration = append(out.CartesianAcceleration, k.acc[i])
+		}
+	}
+	return out
+}
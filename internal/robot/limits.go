@@ -0,0 +1,273 @@
+// ============================================================================
+// internal/robot/limits.go - 서버 측 소프트 리밋
+// ============================================================================
+// 컨트롤러로 JOG 명령을 보내기 전에 마지막으로 알려진 위치(JogState)에
+// 요청한 스텝을 더해 조인트 허용 범위와 카르테시안 작업 영역을 벗어나는지
+// 검사합니다. 컨트롤러 자체 리밋이 너무 넓어 주변 설비와 충돌할 수 있는
+// 셀을 위한 안전 기능입니다.
+//
+// 검사 규칙:
+// - 중단 명령은 항상 통과
+// - 스텝은 MaxStep 이하여야 함 (리밋 비활성화 시에도 적용)
+// - 목표 위치 = 현재 위치 ± 스텝 (해당 축만)
+// - 목표가 범위를 벗어나더라도 범위 안쪽으로 돌아오는 방향이면 허용
+// - 위치는 상태 브로커의 폴링 결과(이 컨트롤러를 거쳐 조회)를 사용하고,
+//   MaxStateAge보다 오래되었을 때만 검사 전에 다시 조회
+// - 현재 위치가 NaN/Inf이면 STATE_UNAVAILABLE로 거부
+// - 카르테시안 검사는 World 좌표 기준 (Tool 모드에서는 근사치)
+// - 축 범위는 로봇 모델의 limit이 기본값이고 설정 파일 limits가 축별로 우선
+// ============================================================================
+
+package robot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 소프트 리밋 기본값 - 위치는 상태 폴링(기본 1초)이 갱신하므로 폴링 주기보다 길게
+const DEFAULT_LIMIT_STATE_MAX_AGE = 1500 * time.Millisecond
+
+// axisLimit 인덱스로 변환된 축 허용 범위
+type axisLimit struct {
+	name string
+	min  float64
+	max  float64
+}
+
+// LimitedController 소프트 리밋을 검사하는 Controller 데코레이터
+type LimitedController struct {
+	inner       Controller
+	enabled     bool
+	maxStep     float64
+	maxStateAge time.Duration
+	model       *Model
+	joints      map[int]axisLimit // JogState.Joint 인덱스 → 범위
+	cartesian   map[int]axisLimit // JogState.Cartesian 인덱스 → 범위
+
+	mu        sync.Mutex
+	lastState *types.JogState
+	lastAt    time.Time
+}
+
+// NewLimitedController 설정의 축 이름을 모델 기준으로 검증하고 LimitedController 생성
+// model이 nil이면 내장 6축 모델을 사용합니다.
+func NewLimitedController(inner Controller, model *Model, cfg types.SoftLimitConfig) (*LimitedController, error) {
+	if model == nil {
+		model = DefaultModel()
+	}
+	c := &LimitedController{
+		inner:       inner,
+		enabled:     cfg.Enabled,
+		maxStep:     cfg.MaxStep,
+		maxStateAge: time.Duration(cfg.MaxStateAgeMs) * time.Millisecond,
+		model:       model,
+		joints:      make(map[int]axisLimit),
+		cartesian:   make(map[int]axisLimit),
+	}
+	if c.maxStateAge <= 0 {
+		c.maxStateAge = DEFAULT_LIMIT_STATE_MAX_AGE
+	}
+
+	// 모델 기본 리밋 → 설정 파일 리밋 순으로 덮어씀
+	if err := resolveLimits(model.jointAxisMap, model.axisLimits(model.info.Joints), c.joints); err != nil {
+		return nil, fmt.Errorf("model.joints: %w", err)
+	}
+	if err := resolveLimits(model.cartesianAxisMap, model.axisLimits(model.info.Cartesian), c.cartesian); err != nil {
+		return nil, fmt.Errorf("model.cartesian: %w", err)
+	}
+	if err := resolveLimits(model.jointAxisMap, cfg.Joints, c.joints); err != nil {
+		return nil, fmt.Errorf("limits.joints: %w", err)
+	}
+	if err := resolveLimits(model.cartesianAxisMap, cfg.Cartesian, c.cartesian); err != nil {
+		return nil, fmt.Errorf("limits.cartesian: %w", err)
+	}
+	return c, nil
+}
+
+// Limits 적용 중인 축 범위 (축 이름 → 범위, 시작 로그용)
+func (c *LimitedController) Limits() (joints map[string]types.AxisLimit, cartesian map[string]types.AxisLimit) {
+	return limitsByName(c.joints), limitsByName(c.cartesian)
+}
+
+// limitsByName 인덱스 맵을 축 이름 맵으로 변환
+func limitsByName(limits map[int]axisLimit) map[string]types.AxisLimit {
+	out := make(map[string]types.AxisLimit, len(limits))
+	for _, limit := range limits {
+		out[limit.name] = types.AxisLimit{Min: limit.min, Max: limit.max}
+	}
+	return out
+}
+
+// resolveLimits 축 이름(별칭 포함)을 JogState 배열 인덱스로 변환
+// out에 이미 있는 범위(모델 기본값)는 덮어쓰고, 같은 limits 안의 중복만 오류로 처리합니다.
+func resolveLimits(axisMap map[string]types.AxisConfig, limits map[string]types.AxisLimit, out map[int]axisLimit) error {
+	// 오류 메시지 순서를 고정하기 위해 이름순으로 처리
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[int]string, len(names))
+	for _, name := range names {
+		config, ok := axisMap[name]
+		if !ok {
+			return fmt.Errorf("지원하지 않는 축: %s", name)
+		}
+		index := config.Axis - 1
+		if prev, dup := seen[index]; dup {
+			return fmt.Errorf("같은 축이 두 번 지정됨: %s, %s", prev, name)
+		}
+		seen[index] = name
+		limit := limits[name]
+		out[index] = axisLimit{name: name, min: limit.Min, max: limit.Max}
+	}
+	return nil
+}
+
+// ============================================================================
+// Controller 구현 (Controller Implementation)
+// ============================================================================
+
+// SendJogCommand 소프트 리밋 검사 후 전송
+func (c *LimitedController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	if cmd.Dir == "stop" {
+		return c.inner.SendJogCommand(cmd)
+	}
+
+	// NaN/음수 스텝은 buildJogCommand에서 INVALID_COMMAND로 거부
+	if c.maxStep > 0 && cmd.Step > c.maxStep {
+		return invalidCommandResponse(fmt.Sprintf("스텝 %.3f이 최대 스텝 %.3f을 초과합니다", cmd.Step, c.maxStep), nil)
+	}
+
+	if c.enabled {
+		if resp, err := c.checkEnvelope(cmd); err != nil {
+			return resp, err
+		}
+	}
+	return c.inner.SendJogCommand(cmd)
+}
+
+// SetJogMode 그대로 전달
+func (c *LimitedController) SetJogMode(mode string) (*types.JogResponse, error) {
+	return c.inner.SetJogMode(mode)
+}
+
+// SetAxis 그대로 전달
+func (c *LimitedController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	return c.inner.SetAxis(axis, robot)
+}
+
+// DisableJog 그대로 전달
+func (c *LimitedController) DisableJog() (*types.JogResponse, error) {
+	return c.inner.DisableJog()
+}
+
+// GetRobotData 상태 조회 후 리밋 검사용 위치로 기억
+func (c *LimitedController) GetRobotData() (*types.JogState, error) {
+	state, err := c.inner.GetRobotData()
+	if err == nil {
+		c.mu.Lock()
+		c.lastState = state
+		c.lastAt = time.Now()
+		c.mu.Unlock()
+	}
+	return state, err
+}
+
+// ConnectionStatus 그대로 전달
+func (c *LimitedController) ConnectionStatus() types.ConnectionStatus {
+	return c.inner.ConnectionStatus()
+}
+
+// ============================================================================
+// 리밋 검사 (Envelope Check)
+// ============================================================================
+
+// checkEnvelope 목표 위치가 허용 범위 안인지 검사 (통과하면 nil 에러)
+func (c *LimitedController) checkEnvelope(cmd types.JogCommand) (*types.JogResponse, error) {
+	mode := cmd.Mode
+	if mode == "" {
+		mode = AxesJoint
+	}
+	step := cmd.Step
+	if step == 0 {
+		step = 1.0 // HTTPController 기본 스텝과 동일
+	}
+	if !(step > 0) {
+		// 잘못된 스텝은 buildJogCommand가 INVALID_COMMAND로 응답
+		return nil, nil
+	}
+
+	axisMap := c.model.axisMap(mode)
+	var limits map[int]axisLimit
+	switch mode {
+	case AxesJoint:
+		limits = c.joints
+	case AxesCartesian:
+		limits = c.cartesian
+	default:
+		// 모드 오류는 HTTPController가 INVALID_COMMAND로 응답
+		return nil, nil
+	}
+
+	config, ok := axisMap[cmd.Axis]
+	if !ok {
+		return nil, nil
+	}
+	index := config.Axis - 1
+	limit, limited := limits[index]
+	if !limited {
+		return nil, nil
+	}
+
+	state, err := c.currentState()
+	if err != nil {
+		return rejectedResponse(types.ErrCodeStateUnavailable, "현재 위치를 알 수 없어 소프트 리밋을 확인할 수 없습니다: "+err.Error())
+	}
+
+	positions := state.Joint
+	if mode == AxesCartesian {
+		positions = state.Cartesian
+	}
+	if index >= len(positions) {
+		return rejectedResponse(types.ErrCodeStateUnavailable, fmt.Sprintf("상태 데이터에 %s 위치가 없습니다", cmd.Axis))
+	}
+
+	current := positions[index]
+	if math.IsNaN(current) || math.IsInf(current, 0) {
+		return rejectedResponse(types.ErrCodeStateUnavailable, fmt.Sprintf("상태 데이터의 %s 위치가 유효하지 않습니다: %v", cmd.Axis, current))
+	}
+
+	delta := step
+	if cmd.Dir == "negative" {
+		delta = -step
+	}
+	target := current + delta
+
+	// 범위 밖에 있더라도 안쪽으로 돌아오는 JOG는 허용
+	if (target > limit.max && delta > 0) || (target < limit.min && delta < 0) {
+		msg := fmt.Sprintf("소프트 리밋 초과: %s %.3f → %.3f (허용 %.3f ~ %.3f)", limit.name, current, target, limit.min, limit.max)
+		logInfo("🚧 %s", msg)
+		return rejectedResponse(types.ErrCodeSoftLimit, msg)
+	}
+	return nil, nil
+}
+
+// currentState 마지막 위치 (MaxStateAge보다 오래되었으면 다시 조회)
+func (c *LimitedController) currentState() (*types.JogState, error) {
+	c.mu.Lock()
+	state, at := c.lastState, c.lastAt
+	c.mu.Unlock()
+
+	if state != nil && time.Since(at) <= c.maxStateAge {
+		return state, nil
+	}
+	return c.GetRobotData()
+}
@@ -0,0 +1,348 @@
+// ============================================================================
+// internal/robot/model.go - 로봇 모델 (축/모드/PID 정의)
+// ============================================================================
+// 축 개수, 별칭, 표시명, 단위, JOG PID, 모드 테이블, 기본 리밋을
+// 코드가 아닌 모델 파일(JSON)로 정의합니다. 4축/5축 로봇이나 레일이
+// 추가된 셀도 재빌드 없이 모델 파일만 바꿔 사용할 수 있습니다.
+//
+// 모델은 컨트롤러마다 선택되며 (controller.model), 지정하지 않으면
+// 기존 6축 정의와 같은 내장 모델을 사용합니다. 예시는 models/ 참고.
+// ============================================================================
+
+package robot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 모델 축 집합 이름 (ModeInfo.Axes, JogCommand.Mode)
+const (
+	AxesJoint     = "joint"
+	AxesCartesian = "cartesian"
+)
+
+// JogState 배열 크기 (jogrefresh.asp 필드 수)
+const (
+	MAX_MODEL_JOINTS    = 12
+	MAX_MODEL_CARTESIAN = 6
+)
+
+// DEFAULT_MODEL_NAME 내장 모델 이름
+const DEFAULT_MODEL_NAME = "default-6axis"
+
+// defaultModelInfo 내장 6축 모델 (모델 파일을 지정하지 않은 경우)
+var defaultModelInfo = types.RobotModel{
+	Name:         DEFAULT_MODEL_NAME,
+	Description:  "6축 조인트 + 6축 카르테시안 (기본)",
+	JointPID:     JointModePID,
+	CartesianPID: CartesianModePID,
+	TriggerPID:   CartesianModePID,
+	Joints: []types.AxisInfo{
+		{DisplayName: "J1", Aliases: []string{"joint1", "j1"}, Unit: "deg"},
+		{DisplayName: "J2", Aliases: []string{"joint2", "j2"}, Unit: "deg"},
+		{DisplayName: "J3", Aliases: []string{"joint3", "j3"}, Unit: "deg"},
+		{DisplayName: "J4", Aliases: []string{"joint4", "j4"}, Unit: "deg"},
+		{DisplayName: "J5", Aliases: []string{"joint5", "j5"}, Unit: "deg"},
+		{DisplayName: "J6", Aliases: []string{"joint6", "j6"}, Unit: "deg"},
+	},
+	Cartesian: []types.AxisInfo{
+		{DisplayName: "X", Aliases: []string{"x"}, Unit: "mm"},
+		{DisplayName: "Y", Aliases: []string{"y"}, Unit: "mm"},
+		{DisplayName: "Z", Aliases: []string{"z"}, Unit: "mm"},
+		{DisplayName: "Rx", Aliases: []string{"rx"}, Unit: "deg"},
+		{DisplayName: "Ry", Aliases: []string{"ry"}, Unit: "deg"},
+		{DisplayName: "Rz", Aliases: []string{"rz"}, Unit: "deg"},
+	},
+	Modes: []types.ModeInfo{
+		{DisplayName: "Computer", ModeNumber: 0, JogEnable: false},
+		{DisplayName: "Joint", ModeNumber: 1, JogEnable: true, Axes: AxesJoint},
+		{DisplayName: "World", ModeNumber: 2, JogEnable: true, Axes: AxesCartesian},
+		{DisplayName: "Tool", ModeNumber: 3, JogEnable: true, Axes: AxesCartesian},
+		{DisplayName: "Free", ModeNumber: 4, JogEnable: true},
+	},
+}
+
+// defaultModel 내장 모델 (검증된 상태로 한 번만 생성)
+var defaultModel = mustModel(defaultModelInfo)
+
+// ============================================================================
+// 모델 (Model)
+// ============================================================================
+
+// Model 검증된 로봇 모델과 조회용 맵
+type Model struct {
+	info             types.RobotModel
+	jointAxisMap     map[string]types.AxisConfig
+	cartesianAxisMap map[string]types.AxisConfig
+	modeMap          map[string]types.ModeConfig
+	modesByNumber    map[int]types.ModeInfo
+}
+
+// DefaultModel 내장 6축 모델
+func DefaultModel() *Model {
+	return defaultModel
+}
+
+// LoadModel 모델 파일 로드 (빈 경로면 내장 모델)
+func LoadModel(path string) (*Model, error) {
+	if path == "" {
+		return defaultModel, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("로봇 모델 파일 읽기 실패 (%s): %w", path, err)
+	}
+
+	var info types.RobotModel
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&info); err != nil {
+		return nil, fmt.Errorf("로봇 모델 파일 파싱 실패 (%s): %w", path, err)
+	}
+
+	model, err := NewModel(info)
+	if err != nil {
+		return nil, fmt.Errorf("로봇 모델 오류 (%s):\n%w", path, err)
+	}
+	return model, nil
+}
+
+// NewModel 모델 정의를 검증하고 축/모드 맵 생성
+func NewModel(info types.RobotModel) (*Model, error) {
+	if info.TriggerPID == "" {
+		info.TriggerPID = info.CartesianPID
+	}
+	if err := validateModel(info); err != nil {
+		return nil, err
+	}
+
+	m := &Model{
+		info:             info,
+		jointAxisMap:     generateAxisMap(info.JointPID, info.Joints),
+		cartesianAxisMap: generateAxisMap(info.CartesianPID, info.Cartesian),
+		modeMap:          generateModeMap(info.Modes),
+		modesByNumber:    make(map[int]types.ModeInfo, len(info.Modes)),
+	}
+	for _, mode := range info.Modes {
+		m.modesByNumber[mode.ModeNumber] = mode
+	}
+	return m, nil
+}
+
+// mustModel 내장 모델 생성 (정의 오류면 패닉)
+func mustModel(info types.RobotModel) *Model {
+	m, err := NewModel(info)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Name 모델 이름
+func (m *Model) Name() string {
+	return m.info.Name
+}
+
+// Info 모델 정의 (API 응답용)
+func (m *Model) Info() types.RobotModel {
+	return m.info
+}
+
+// axisMap JOG 모드("joint", "cartesian")의 축 맵 (없으면 nil)
+func (m *Model) axisMap(mode string) map[string]types.AxisConfig {
+	switch mode {
+	case AxesJoint:
+		return m.jointAxisMap
+	case AxesCartesian:
+		return m.cartesianAxisMap
+	}
+	return nil
+}
+
+// modeText 모드 번호를 표시명으로 변환
+func (m *Model) modeText(mode int) string {
+	if info, ok := m.modesByNumber[mode]; ok {
+		return info.DisplayName
+	}
+	return fmt.Sprintf("Mode%d", mode)
+}
+
+// axisText 모드와 축 번호(1부터)에 따른 축 표시명
+func (m *Model) axisText(jogMode int, axisNum int) string {
+	axisInfos := m.info.Cartesian
+	joint := m.modesByNumber[jogMode].Axes == AxesJoint
+	if joint {
+		axisInfos = m.info.Joints
+	}
+
+	if axisNum >= 1 && axisNum <= len(axisInfos) {
+		return axisInfos[axisNum-1].DisplayName
+	}
+	if joint {
+		return fmt.Sprintf("J%d", axisNum)
+	}
+	return fmt.Sprintf("Axis%d", axisNum)
+}
+
+// axisRef 모델 축 → JogState 배열 위치
+type axisRef struct {
+	name  string // 모델 축의 첫 번째 별칭
+	group string // AxesJoint, AxesCartesian
+	index int
+}
+
+// value 상태에서 축 값 조회
+func (a axisRef) value(state *types.JogState) float64 {
+	if a.group == AxesJoint {
+		return getSafeValue(state.Joint, a.index)
+	}
+	return getSafeValue(state.Cartesian, a.index)
+}
+
+// axisRefs 모델의 모든 축 (조인트 → 카르테시안 순서)
+func (m *Model) axisRefs() []axisRef {
+	refs := make([]axisRef, 0, len(m.info.Joints)+len(m.info.Cartesian))
+	for i, axis := range m.info.Joints {
+		refs = append(refs, axisRef{name: axis.Aliases[0], group: AxesJoint, index: i})
+	}
+	for i, axis := range m.info.Cartesian {
+		refs = append(refs, axisRef{name: axis.Aliases[0], group: AxesCartesian, index: i})
+	}
+	return refs
+}
+
+// findAxisRef 별칭으로 축 조회 (조인트 우선)
+func (m *Model) findAxisRef(alias string) (axisRef, bool) {
+	alias = strings.ToLower(alias)
+	for i, axis := range m.info.Joints {
+		for _, a := range axis.Aliases {
+			if a == alias {
+				return axisRef{name: axis.Aliases[0], group: AxesJoint, index: i}, true
+			}
+		}
+	}
+	for i, axis := range m.info.Cartesian {
+		for _, a := range axis.Aliases {
+			if a == alias {
+				return axisRef{name: axis.Aliases[0], group: AxesCartesian, index: i}, true
+			}
+		}
+	}
+	return axisRef{}, false
+}
+
+// axisLimits 모델에 정의된 기본 리밋 (축 첫 번째 별칭 → 범위)
+func (m *Model) axisLimits(axes []types.AxisInfo) map[string]types.AxisLimit {
+	limits := make(map[string]types.AxisLimit)
+	for _, axis := range axes {
+		if axis.Limit != nil {
+			limits[axis.Aliases[0]] = *axis.Limit
+		}
+	}
+	return limits
+}
+
+// ============================================================================
+// 모델 검증 (Validation)
+// ============================================================================
+
+// validateModel 모델 정의 검증 (모든 오류를 모아서 반환)
+func validateModel(info types.RobotModel) error {
+	var errs []error
+	fail := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if strings.TrimSpace(info.Name) == "" {
+		fail("name", "비어 있을 수 없습니다")
+	}
+	pids := []struct{ field, pid string }{
+		{"joint_pid", info.JointPID},
+		{"cartesian_pid", info.CartesianPID},
+		{"trigger_pid", info.TriggerPID},
+	}
+	for _, p := range pids {
+		if _, err := strconv.Atoi(p.pid); err != nil {
+			fail(p.field, "숫자 PID여야 합니다 (값: %q)", p.pid)
+		}
+	}
+
+	if len(info.Joints) == 0 || len(info.Joints) > MAX_MODEL_JOINTS {
+		fail("joints", "1-%d개여야 합니다 (값: %d개)", MAX_MODEL_JOINTS, len(info.Joints))
+	}
+	if len(info.Cartesian) > MAX_MODEL_CARTESIAN {
+		fail("cartesian", "최대 %d개입니다 (값: %d개)", MAX_MODEL_CARTESIAN, len(info.Cartesian))
+	}
+	validateModelAxes("joints", info.Joints, fail)
+	validateModelAxes("cartesian", info.Cartesian, fail)
+
+	if len(info.Modes) == 0 {
+		fail("modes", "최소 1개의 모드가 필요합니다")
+	}
+	names := make(map[string]bool)
+	numbers := make(map[int]bool)
+	for i, mode := range info.Modes {
+		field := fmt.Sprintf("modes[%d]", i)
+		name := strings.ToLower(mode.DisplayName)
+		switch {
+		case name == "":
+			fail(field+".name", "비어 있을 수 없습니다")
+		case names[name]:
+			fail(field+".name", "중복된 모드: %s", mode.DisplayName)
+		}
+		if numbers[mode.ModeNumber] {
+			fail(field+".number", "중복된 모드 번호: %d", mode.ModeNumber)
+		}
+		names[name], numbers[mode.ModeNumber] = true, true
+
+		switch mode.Axes {
+		case "", AxesJoint:
+		case AxesCartesian:
+			if len(info.Cartesian) == 0 {
+				fail(field+".axes", "cartesian 축이 정의되지 않았습니다")
+			}
+		default:
+			fail(field+".axes", "joint, cartesian 또는 빈 값이어야 합니다 (값: %q)", mode.Axes)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateModelAxes 축 목록 검증 (별칭 중복, 리밋 범위)
+func validateModelAxes(field string, axes []types.AxisInfo, fail func(string, string, ...interface{})) {
+	seen := make(map[string]bool)
+	for i, axis := range axes {
+		axisField := fmt.Sprintf("%s[%d]", field, i)
+		if axis.DisplayName == "" {
+			fail(axisField+".name", "비어 있을 수 없습니다")
+		}
+		if len(axis.Aliases) == 0 {
+			fail(axisField+".aliases", "최소 1개의 별칭이 필요합니다")
+		}
+		for _, alias := range axis.Aliases {
+			if alias == "" || alias != strings.ToLower(alias) {
+				fail(axisField+".aliases", "소문자 별칭이어야 합니다 (값: %q)", alias)
+			}
+			if seen[alias] {
+				fail(axisField+".aliases", "중복된 별칭: %s", alias)
+			}
+			seen[alias] = true
+		}
+		if l := axis.Limit; l != nil {
+			if math.IsNaN(l.Min) || math.IsNaN(l.Max) || math.IsInf(l.Min, 0) || math.IsInf(l.Max, 0) || l.Min >= l.Max {
+				fail(axisField+".limit", "min < max인 유한한 값이어야 합니다 (값: %v ~ %v)", l.Min, l.Max)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// ============================================================================
+// internal/robot/observed.go - 컨트롤러 요청 관측 (메트릭용)
+// ============================================================================
+// HTTPController 바로 위에 두어 실제로 컨트롤러에 보낸 요청마다 종류와
+// 걸린 시간, 결과를 관측자에게 전달합니다. 큐 대기 시간은 포함하지 않습니다.
+//
+// 요청 종류:
+// - jog: JOG 명령, stop: 중단 명령 (Dir "stop")
+// - mode: JOG 모드 변경, axis: 축 선택, disable: JOG 비활성화
+// - poll: 상태 조회 (재연결 대기로 건너뛴 조회도 ErrReconnectBackoff로 전달)
+// ============================================================================
+
+package robot
+
+import (
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 관측 요청 종류 (CommandObserver command)
+const (
+	CommandJog     = "jog"
+	CommandStop    = "stop"
+	CommandMode    = "mode"
+	CommandAxis    = "axis"
+	CommandDisable = "disable"
+	CommandPoll    = "poll"
+)
+
+// CommandObserver 컨트롤러 요청 하나가 끝날 때 호출 (resp는 poll이면 nil)
+type CommandObserver func(command string, elapsed time.Duration, resp *types.JogResponse, err error)
+
+// ObservedController 요청마다 CommandObserver를 호출하는 Controller 데코레이터
+type ObservedController struct {
+	inner   Controller
+	observe CommandObserver
+}
+
+// NewObservedController ObservedController 생성
+func NewObservedController(inner Controller, observe CommandObserver) *ObservedController {
+	return &ObservedController{inner: inner, observe: observe}
+}
+
+// SendJogCommand 전송 시간 관측 (중단 명령은 stop)
+func (c *ObservedController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	command := CommandJog
+	if cmd.Dir == "stop" {
+		command = CommandStop
+	}
+	start := time.Now()
+	resp, err := c.inner.SendJogCommand(cmd)
+	c.observe(command, time.Since(start), resp, err)
+	return resp, err
+}
+
+// SetJogMode 전송 시간 관측
+func (c *ObservedController) SetJogMode(mode string) (*types.JogResponse, error) {
+	start := time.Now()
+	resp, err := c.inner.SetJogMode(mode)
+	c.observe(CommandMode, time.Since(start), resp, err)
+	return resp, err
+}
+
+// SetAxis 전송 시간 관측
+func (c *ObservedController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	start := time.Now()
+	resp, err := c.inner.SetAxis(axis, robot)
+	c.observe(CommandAxis, time.Since(start), resp, err)
+	return resp, err
+}
+
+// DisableJog 전송 시간 관측
+func (c *ObservedController) DisableJog() (*types.JogResponse, error) {
+	start := time.Now()
+	resp, err := c.inner.DisableJog()
+	c.observe(CommandDisable, time.Since(start), resp, err)
+	return resp, err
+}
+
+// GetRobotData 조회 시간 관측
+func (c *ObservedController) GetRobotData() (*types.JogState, error) {
+	start := time.Now()
+	state, err := c.inner.GetRobotData()
+	c.observe(CommandPoll, time.Since(start), nil, err)
+	return state, err
+}
+
+// ConnectionStatus 그대로 전달
+func (c *ObservedController) ConnectionStatus() types.ConnectionStatus {
+	return c.inner.ConnectionStatus()
+}
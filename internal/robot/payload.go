@@ -0,0 +1,551 @@
+// ============================================================================
+// internal/robot/payload.go - jogrefresh.asp 응답 레이아웃 (펌웨어별 필드 위치)
+// ============================================================================
+// 상태 조회 응답은 파이프(|)로 구분된 필드 목록이며, 컨트롤러 펌웨어
+// 버전마다 필드가 추가되거나 위치가 바뀝니다. 필드 위치를 코드 대신
+// 레이아웃 정의(JSON)로 두고 컨트롤러마다 선택합니다 (controller.payload_layout).
+//
+// 해석 규칙:
+// - required가 아닌 빈 필드는 0 (6축 로봇의 Joint7-12처럼 컨트롤러가 비워 보내는 값)
+// - 읽지 못한 값(숫자가 아니거나 NaN/Inf, 비어 있는 required 필드)은 0으로 두고
+//   StateMeta.ParseErrors에 필드별로 기록
+//   (required 필드면 조회 전체를 실패로 처리 - 잘못된 위치로 리밋 검사를 하지 않도록)
+// - 레이아웃에 없는 비어 있지 않은 필드는 StateMeta.UnknownFields에 원본 그대로 보관
+//
+// 지정하지 않으면 기존 고정 위치와 같은 내장 레이아웃(jogrefresh-v1)을 사용합니다.
+// 예시는 layouts/ 참고.
+// ============================================================================
+
+package robot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 레이아웃 대상 (PayloadField.Target)
+const (
+	PayloadCartesian    = "cartesian"
+	PayloadJoint        = "joint"
+	PayloadTool         = "tool"
+	PayloadAxisCount    = "axis_count"
+	PayloadAllowJog     = "allow_jog"
+	PayloadJogMode      = "jog_mode"
+	PayloadPowerState   = "power_state"
+	PayloadErrorDesc    = "error_desc"
+	PayloadSelectedAxis = "selected_axis"
+	PayloadIgnore       = "ignore" // 알고 있지만 사용하지 않는 필드 (UnknownFields에 넣지 않음)
+)
+
+// 레이아웃 기본값
+const (
+	DEFAULT_PAYLOAD_LAYOUT    = "jogrefresh-v1"
+	DEFAULT_PAYLOAD_SEPARATOR = "|"
+	MAX_PAYLOAD_FIELDS        = 256
+	MAX_TOOL_FIELDS           = 6
+	MAX_POOLED_PAYLOAD_BYTES  = 64 << 10 // 이보다 커진 읽기 버퍼는 풀에 돌려주지 않음
+)
+
+// ErrPayload 응답을 레이아웃대로 해석하지 못함 (필드 수 부족, required 필드 오류)
+var ErrPayload = errors.New("상태 응답 해석 실패")
+
+// payloadArraySizes 배열 대상의 JogState 배열 크기
+var payloadArraySizes = map[string]int{
+	PayloadCartesian: MAX_MODEL_CARTESIAN,
+	PayloadJoint:     MAX_MODEL_JOINTS,
+	PayloadTool:      MAX_TOOL_FIELDS,
+}
+
+// defaultLayoutInfo 내장 레이아웃 - 기존 고정 위치 (jData[0-5] 카르테시안, [6-17] 조인트,
+// [19-23] 상태, [24] 툴). [18]은 선택된 축이지만 기존처럼 사용하지 않음 (UnknownFields에 보관)
+// Joint7-12([12-17])는 6축 로봇에서 비어 오므로 required가 아님
+var defaultLayoutInfo = types.PayloadLayout{
+	Name:        DEFAULT_PAYLOAD_LAYOUT,
+	Description: "기본 jogrefresh.asp (카르테시안 6, 조인트 12, 상태 5, 툴 1)",
+	MinFields:   25,
+	Fields: []types.PayloadField{
+		{Target: PayloadCartesian, Index: 0, Count: 6, Required: true},
+		{Target: PayloadJoint, Index: 6, Count: 6, Required: true},
+		{Target: PayloadJoint, Index: 12, Count: 6, Slot: 6},
+		{Target: PayloadAxisCount, Index: 19},
+		{Target: PayloadAllowJog, Index: 20},
+		{Target: PayloadJogMode, Index: 21},
+		{Target: PayloadPowerState, Index: 22},
+		{Target: PayloadErrorDesc, Index: 23},
+		{Target: PayloadTool, Index: 24, Split: ","},
+	},
+}
+
+// builtinLayouts 이름으로 선택할 수 있는 내장 레이아웃
+var builtinLayouts = map[string]*PayloadLayout{
+	DEFAULT_PAYLOAD_LAYOUT: mustPayloadLayout(defaultLayoutInfo),
+}
+
+// ============================================================================
+// 레이아웃 (Payload Layout)
+// ============================================================================
+
+// payloadKind 필드 해석 방법 (대상 이름을 매 필드마다 비교하지 않도록 미리 변환)
+type payloadKind int
+
+const (
+	kindUnknown payloadKind = iota // 레이아웃에 없는 필드
+	kindIgnore
+	kindArray
+	kindAxisCount
+	kindAllowJog
+	kindJogMode
+	kindPowerState
+	kindErrorDesc
+	kindSelectedAxis
+)
+
+// payloadKinds 대상 이름 → 해석 방법
+var payloadKinds = map[string]payloadKind{
+	PayloadCartesian:    kindArray,
+	PayloadJoint:        kindArray,
+	PayloadTool:         kindArray,
+	PayloadAxisCount:    kindAxisCount,
+	PayloadAllowJog:     kindAllowJog,
+	PayloadJogMode:      kindJogMode,
+	PayloadPowerState:   kindPowerState,
+	PayloadErrorDesc:    kindErrorDesc,
+	PayloadSelectedAxis: kindSelectedAxis,
+	PayloadIgnore:       kindIgnore,
+}
+
+// payloadOffsets 배열 대상의 payloadValues 시작 위치
+var payloadOffsets = map[string]int{
+	PayloadCartesian: 0,
+	PayloadJoint:     MAX_MODEL_CARTESIAN,
+	PayloadTool:      MAX_MODEL_CARTESIAN + MAX_MODEL_JOINTS,
+}
+
+// payloadSlot 필드 위치 하나의 해석 방법
+type payloadSlot struct {
+	kind     payloadKind
+	target   string // 오류 표시용 대상 이름
+	offset   int    // 배열 대상: payloadValues 시작 위치
+	size     int    // 배열 대상: 배열 크기
+	slot     int    // 배열 대상: 배열 위치
+	split    string
+	required bool
+}
+
+// PayloadLayout 검증된 레이아웃 (필드 위치별 해석 표)
+type PayloadLayout struct {
+	info      types.PayloadLayout
+	separator string
+	minFields int
+	slots     []payloadSlot // 필드 위치 → 해석 방법
+}
+
+// DefaultPayloadLayout 내장 기본 레이아웃
+func DefaultPayloadLayout() *PayloadLayout {
+	return builtinLayouts[DEFAULT_PAYLOAD_LAYOUT]
+}
+
+// LoadPayloadLayout 내장 레이아웃 이름 또는 레이아웃 파일 경로로 로드 (빈 값이면 기본)
+func LoadPayloadLayout(nameOrPath string) (*PayloadLayout, error) {
+	if nameOrPath == "" {
+		return DefaultPayloadLayout(), nil
+	}
+	if layout, ok := builtinLayouts[nameOrPath]; ok {
+		return layout, nil
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("응답 레이아웃 파일 읽기 실패 (%s - 내장 레이아웃: %s): %w", nameOrPath, DEFAULT_PAYLOAD_LAYOUT, err)
+	}
+
+	var info types.PayloadLayout
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&info); err != nil {
+		return nil, fmt.Errorf("응답 레이아웃 파일 파싱 실패 (%s): %w", nameOrPath, err)
+	}
+
+	layout, err := NewPayloadLayout(info)
+	if err != nil {
+		return nil, fmt.Errorf("응답 레이아웃 오류 (%s):\n%w", nameOrPath, err)
+	}
+	return layout, nil
+}
+
+// NewPayloadLayout 레이아웃 정의를 검증하고 필드 위치별 해석 표 생성
+func NewPayloadLayout(info types.PayloadLayout) (*PayloadLayout, error) {
+	if info.Separator == "" {
+		info.Separator = DEFAULT_PAYLOAD_SEPARATOR
+	}
+	if err := validatePayloadLayout(info); err != nil {
+		return nil, err
+	}
+
+	last := 0
+	for _, f := range info.Fields {
+		if end := f.Index + fieldCount(f); end > last {
+			last = end
+		}
+	}
+	l := &PayloadLayout{
+		info:      info,
+		separator: info.Separator,
+		minFields: info.MinFields,
+		slots:     make([]payloadSlot, last),
+	}
+	if l.minFields == 0 {
+		l.minFields = last
+	}
+	for _, f := range info.Fields {
+		for i := 0; i < fieldCount(f); i++ {
+			l.slots[f.Index+i] = payloadSlot{
+				kind:     payloadKinds[f.Target],
+				target:   f.Target,
+				offset:   payloadOffsets[f.Target],
+				size:     payloadArraySizes[f.Target],
+				slot:     f.Slot + i,
+				split:    f.Split,
+				required: f.Required,
+			}
+		}
+	}
+	return l, nil
+}
+
+// mustPayloadLayout 내장 레이아웃 생성 (오류면 패닉 - 코드 오류)
+func mustPayloadLayout(info types.PayloadLayout) *PayloadLayout {
+	l, err := NewPayloadLayout(info)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Name 레이아웃 이름
+func (l *PayloadLayout) Name() string {
+	return l.info.Name
+}
+
+// Info 레이아웃 정의
+func (l *PayloadLayout) Info() types.PayloadLayout {
+	return l.info
+}
+
+// fieldCount 필드 정의가 차지하는 필드 수 (Count 기본 1)
+func fieldCount(f types.PayloadField) int {
+	if f.Count < 1 {
+		return 1
+	}
+	return f.Count
+}
+
+// validatePayloadLayout 레이아웃 검증 (모든 오류를 모아서 반환)
+func validatePayloadLayout(info types.PayloadLayout) error {
+	var errs []error
+	fail := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if strings.TrimSpace(info.Name) == "" {
+		fail("name", "비어 있을 수 없습니다")
+	}
+	if info.MinFields < 0 || info.MinFields > MAX_PAYLOAD_FIELDS {
+		fail("min_fields", "0-%d 범위여야 합니다 (값: %d)", MAX_PAYLOAD_FIELDS, info.MinFields)
+	}
+	if len(info.Fields) == 0 {
+		fail("fields", "최소 1개의 필드가 필요합니다")
+	}
+
+	used := make(map[int]string)
+	for i, f := range info.Fields {
+		field := fmt.Sprintf("fields[%d]", i)
+		size, isArray := payloadArraySizes[f.Target]
+		switch {
+		case isArray:
+			if f.Slot < 0 || f.Slot+fieldCount(f) > size {
+				fail(field, "%s 배열 범위(0-%d)를 벗어납니다 (slot %d, count %d)", f.Target, size-1, f.Slot, fieldCount(f))
+			}
+			if f.Split != "" && f.Count > 1 {
+				fail(field, "split과 count를 함께 사용할 수 없습니다")
+			}
+		case payloadKinds[f.Target] != kindUnknown:
+			if f.Count > 1 || f.Slot != 0 || f.Split != "" {
+				fail(field, "%s는 필드 하나만 사용합니다 (count, slot, split 불가)", f.Target)
+			}
+		default:
+			fail(field+".target", "알 수 없는 대상: %q", f.Target)
+			continue
+		}
+
+		if f.Index < 0 || f.Index+fieldCount(f) > MAX_PAYLOAD_FIELDS {
+			fail(field+".index", "0-%d 범위여야 합니다 (값: %d, count %d)", MAX_PAYLOAD_FIELDS-1, f.Index, fieldCount(f))
+			continue
+		}
+		for n := f.Index; n < f.Index+fieldCount(f); n++ {
+			if prev, ok := used[n]; ok {
+				fail(field+".index", "필드 %d가 %s와 겹칩니다", n, prev)
+			}
+			used[n] = field
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ============================================================================
+// 해석 (Parsing)
+// ============================================================================
+
+// Parse 응답 텍스트를 레이아웃대로 JogState로 변환
+// body를 복사하거나 필드 목록을 만들지 않고 구분자 위치만 따라가며 읽습니다.
+// 반환한 상태는 body를 참조하지 않으므로 호출자는 body 버퍼를 재사용해도 됩니다.
+func (l *PayloadLayout) Parse(body []byte, model *Model) (*types.JogState, error) {
+	state, values := newPayloadState()
+	// 선택된 축 필드가 없는 레이아웃은 기존처럼 1 (별도 API에서 가져와야 함)
+	state.Status.SelectedAxis = 1
+
+	sc := payloadScanner{data: bytes.TrimSpace(body), sep: l.separator}
+	requiredFailed := false
+	n := 0
+	for ; ; n++ {
+		raw, ok := sc.next()
+		if !ok {
+			break
+		}
+		if n >= len(l.slots) || l.slots[n].kind == kindUnknown {
+			if v := bytes.TrimSpace(raw); len(v) > 0 {
+				state.Meta.UnknownFields = append(state.Meta.UnknownFields, types.PayloadRawField{Index: n, Value: string(v)})
+			}
+			continue
+		}
+
+		slot := &l.slots[n]
+		before := len(state.Meta.ParseErrors)
+		slot.parse(state, values, n, raw)
+		if len(state.Meta.ParseErrors) > before && slot.required {
+			requiredFailed = true
+		}
+	}
+
+	if n < l.minFields {
+		return nil, fmt.Errorf("%w: 응답 데이터가 부족합니다: %d개 항목 (레이아웃 %s는 최소 %d개)", ErrPayload, n, l.info.Name, l.minFields)
+	}
+	if requiredFailed {
+		return nil, &PayloadError{Layout: l.info.Name, Fields: state.Meta.ParseErrors}
+	}
+
+	state.Status.JogModeText = model.modeText(state.Status.JogMode)
+	state.Status.SelectedAxisText = model.axisText(state.Status.JogMode, state.Status.SelectedAxis)
+	state.Meta.Layout = l.info.Name
+	return state, nil
+}
+
+// payloadBufPool 응답 읽기 버퍼 (조회마다 새로 할당하지 않도록 재사용)
+var payloadBufPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// readPayload 응답 본문을 풀의 버퍼로 읽음 (다 쓰면 releasePayload로 반환)
+// MAX_POOLED_PAYLOAD_BYTES보다 긴 응답은 상태 응답이 아니므로(프록시 오류 페이지 등)
+// 버퍼를 키우지 않고 ErrPayload로 실패합니다.
+func readPayload(r io.Reader) (*bytes.Buffer, error) {
+	buf := payloadBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if _, err := buf.ReadFrom(io.LimitReader(r, MAX_POOLED_PAYLOAD_BYTES+1)); err != nil {
+		releasePayload(buf)
+		return nil, err
+	}
+	if buf.Len() > MAX_POOLED_PAYLOAD_BYTES {
+		releasePayload(buf)
+		return nil, fmt.Errorf("%w: 응답이 %d바이트를 넘습니다", ErrPayload, MAX_POOLED_PAYLOAD_BYTES)
+	}
+	return buf, nil
+}
+
+// releasePayload 읽기 버퍼를 풀에 반환
+func releasePayload(buf *bytes.Buffer) {
+	if buf.Cap() <= MAX_POOLED_PAYLOAD_BYTES {
+		payloadBufPool.Put(buf)
+	}
+}
+
+// payloadFingerprint 원본 응답 해시 (FNV-1a 64비트, 앞뒤 공백 제외) - 응답이 바뀌었는지 비교용
+func payloadFingerprint(body []byte) string {
+	const offset, prime = 14695981039346656037, 1099511628211
+	h := uint64(offset)
+	for _, c := range bytes.TrimSpace(body) {
+		h ^= uint64(c)
+		h *= prime
+	}
+	return strconv.FormatUint(h, 16)
+}
+
+// payloadValues 상태 하나의 카르테시안/조인트/툴 값 (한 번에 할당)
+type payloadValues [MAX_MODEL_CARTESIAN + MAX_MODEL_JOINTS + MAX_TOOL_FIELDS]float64
+
+// newPayloadState 빈 상태 생성 - 세 배열이 payloadValues 하나를 나눠 씀 (cap을 잘라 서로 침범하지 않음)
+func newPayloadState() (*types.JogState, *payloadValues) {
+	values := new(payloadValues)
+	const joint, tool = MAX_MODEL_CARTESIAN, MAX_MODEL_CARTESIAN + MAX_MODEL_JOINTS
+	return &types.JogState{
+		Cartesian: values[:joint:joint],
+		Joint:     values[joint:tool:tool],
+		ToolData:  values[tool:],
+	}, values
+}
+
+// payloadScanner 구분자로 나눈 필드를 앞에서부터 하나씩 반환 (할당 없음)
+type payloadScanner struct {
+	data []byte
+	sep  string
+	pos  int // 다음 필드 시작 위치 (len(data)+1이면 끝)
+}
+
+// next 다음 필드 (빈 응답은 빈 필드 하나 - strings.Split과 같은 규칙)
+func (s *payloadScanner) next() ([]byte, bool) {
+	if s.pos > len(s.data) {
+		return nil, false
+	}
+	rest := s.data[s.pos:]
+	var end int
+	if len(s.sep) == 1 {
+		end = bytes.IndexByte(rest, s.sep[0])
+	} else {
+		end = bytes.Index(rest, []byte(s.sep))
+	}
+	if end < 0 {
+		s.pos = len(s.data) + 1
+		return rest, true
+	}
+	s.pos += end + len(s.sep)
+	return rest[:end], true
+}
+
+// parse 필드 하나를 대상에 기록 (읽지 못한 값은 state.Meta.ParseErrors에 추가)
+func (s *payloadSlot) parse(state *types.JogState, values *payloadValues, index int, raw []byte) {
+	switch s.kind {
+	case kindIgnore:
+		return
+	case kindErrorDesc:
+		if v := bytes.TrimSpace(raw); len(v) > 0 {
+			state.Status.ErrorDesc = string(v)
+		}
+		return
+	case kindArray:
+		if s.split == "" {
+			s.parseValue(state, values, index, s.slot, raw)
+			return
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			if s.required {
+				state.Meta.ParseErrors = append(state.Meta.ParseErrors, fieldError(index, s.target, raw, errEmptyRequired))
+			}
+			return
+		}
+		sc := payloadScanner{data: raw, sep: s.split}
+		for j := s.slot; j < s.size; j++ {
+			part, ok := sc.next()
+			if !ok {
+				break
+			}
+			s.parseValue(state, values, index, j, part)
+		} // 배열보다 많은 값은 버림 (기존 동작)
+		return
+	}
+
+	v, problem := s.value(raw)
+	if problem != "" {
+		state.Meta.ParseErrors = append(state.Meta.ParseErrors, fieldError(index, s.target, raw, problem))
+		return
+	}
+	switch s.kind {
+	case kindAxisCount:
+		state.Status.AxisCount = int(v)
+	case kindAllowJog:
+		state.Status.AllowJog = v > 0
+	case kindJogMode:
+		state.Status.JogMode = int(v)
+	case kindPowerState:
+		state.Status.PowerState = int(v)
+	case kindSelectedAxis:
+		state.Status.SelectedAxis = int(v)
+	}
+}
+
+// parseValue 배열 값 하나 기록 (읽지 못하면 0으로 두고 오류 추가)
+func (s *payloadSlot) parseValue(state *types.JogState, values *payloadValues, index, slot int, raw []byte) {
+	v, problem := s.value(raw)
+	if problem != "" {
+		state.Meta.ParseErrors = append(state.Meta.ParseErrors, fieldError(index, s.target+"["+strconv.Itoa(slot)+"]", raw, problem))
+		return
+	}
+	values[s.offset+slot] = v
+}
+
+// 필드 오류 사유
+const (
+	errNotNumber     = "숫자가 아닙니다"
+	errNotFinite     = "유한한 숫자가 아닙니다"
+	errEmptyRequired = "required 필드가 비어 있습니다"
+)
+
+// value 필드 값 하나를 숫자로 변환 (읽지 못하면 오류 사유 반환)
+// 빈 값은 required가 아닌 필드에서만 0으로 허용합니다.
+func (s *payloadSlot) value(raw []byte) (float64, string) {
+	if len(bytes.TrimSpace(raw)) == 0 && s.required {
+		return 0, errEmptyRequired
+	}
+	v, ok := parseFloatBytes(raw)
+	if !ok {
+		return 0, errNotNumber
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errNotFinite
+	}
+	return v, ""
+}
+
+// fieldError 필드 오류 (raw는 body 버퍼를 가리키므로 복사)
+func fieldError(index int, field string, raw []byte, problem string) types.PayloadFieldError {
+	v := string(bytes.TrimSpace(raw))
+	return types.PayloadFieldError{Index: index, Field: field, Raw: v, Error: fmt.Sprintf("%s: %q", problem, v)}
+}
+
+// parseFloatBytes 필드 값을 float64로 파싱 (빈 값은 0, 숫자가 아니면 false)
+// NaN/Inf도 그대로 반환하므로 호출자가 유한한 값인지 확인합니다.
+// string 변환이 함수 밖으로 나가지 않아 짧은 값은 힙 할당 없이 변환됩니다.
+func parseFloatBytes(b []byte) (float64, bool) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return 0, true
+	}
+	v, err := strconv.ParseFloat(string(b), 64)
+	return v, err == nil
+}
+
+// PayloadError required 필드를 읽지 못해 상태를 만들지 않음
+type PayloadError struct {
+	Layout string
+	Fields []types.PayloadFieldError
+}
+
+func (e *PayloadError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, fmt.Sprintf("%s(#%d)=%q: %s", f.Field, f.Index, f.Raw, f.Error))
+	}
+	return fmt.Sprintf("%v: 레이아웃 %s 필드 오류 %d개: %s", ErrPayload, e.Layout, len(e.Fields), strings.Join(parts, "; "))
+}
+
+// Unwrap errors.Is(err, ErrPayload)
+func (e *PayloadError) Unwrap() error {
+	return ErrPayload
+}
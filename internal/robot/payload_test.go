@@ -0,0 +1,297 @@
+// ============================================================================
+// internal/robot/payload_test.go - 상태 응답 해석 테스트 및 벤치마크
+// ============================================================================
+// 레이아웃 해석 규칙(필드별 오류, required 필드, UnknownFields, 레이아웃
+// 파일)과 응답 크기 제한을 확인하고, 조회마다 실행되는 jogrefresh.asp 해석
+// 비용을 기존 방식(strings.Split + fmt.Sscanf)과 비교합니다.
+//
+//	go test ./internal/robot -run '^$' -bench Payload -benchmem
+// ============================================================================
+
+package robot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// benchPayload 6축 로봇의 일반적인 응답 (Joint7-12는 빈 값)
+const benchPayload = "312.457|-125.882|415.003|179.998|-0.012|45.271|" +
+	"12.504|-35.117|88.930|0.004|36.221|-77.765||||||" +
+	"|3|6|1|2|1||0.000,0.000,120.500,0.000,0.000,0.000\r\n"
+
+// BenchmarkParsePayload 해석만 (응답 본문은 이미 읽은 상태)
+func BenchmarkParsePayload(b *testing.B) {
+	body := []byte(benchPayload)
+	model := DefaultModel()
+	layout := DefaultPayloadLayout()
+	checkSameAsLegacy(b, body, model, layout)
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := legacyParseRobotData(body, model); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := layout.Parse(body, model); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkReadPayload 응답 본문 읽기 + 해석 (GetRobotData에서 HTTP 요청을 뺀 부분)
+func BenchmarkReadPayload(b *testing.B) {
+	body := []byte(benchPayload)
+	model := DefaultModel()
+	layout := DefaultPayloadLayout()
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := io.ReadAll(bytes.NewReader(body))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := legacyParseRobotData(data, model); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		r := bytes.NewReader(body)
+		for i := 0; i < b.N; i++ {
+			r.Reset(body)
+			buf, err := readPayload(r)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, err = layout.Parse(buf.Bytes(), model)
+			releasePayload(buf)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// ============================================================================
+// 해석 테스트 (Parse Tests)
+// ============================================================================
+
+// testPayload benchPayload 필드를 바꾼 응답 (index → 값, 선택된 축 [18]은 기본 빈 값)
+func testPayload(fields map[int]string, extra ...string) []byte {
+	parts := strings.Split(strings.TrimSpace(benchPayload), "|")
+	parts[18] = ""
+	for i, v := range fields {
+		parts[i] = v
+	}
+	return []byte(strings.Join(append(parts, extra...), "|"))
+}
+
+func TestPayloadLayoutParse(t *testing.T) {
+	model := DefaultModel()
+	layout := DefaultPayloadLayout()
+
+	tests := []struct {
+		name        string
+		body        []byte
+		wantErr     bool     // 조회 전체 실패 (ErrPayload)
+		wantErrors  []string // ParseErrors의 Field (순서대로)
+		wantUnknown []int    // UnknownFields의 Index
+	}{
+		{name: "valid", body: testPayload(nil)},
+		{name: "optional joint not a number", body: testPayload(map[int]string{13: "abc"}), wantErrors: []string{"joint[7]"}},
+		{name: "optional joint infinite", body: testPayload(map[int]string{14: "Inf"}), wantErrors: []string{"joint[8]"}},
+		{name: "status field not a number", body: testPayload(map[int]string{21: "x"}), wantErrors: []string{"jog_mode"}},
+		{name: "tool value not a number", body: testPayload(map[int]string{24: "1,oops,3"}), wantErrors: []string{"tool[1]"}},
+		{name: "required cartesian NaN", body: testPayload(map[int]string{2: "NaN"}), wantErr: true},
+		{name: "required joint not a number", body: testPayload(map[int]string{7: "--"}), wantErr: true},
+		{name: "required field empty", body: testPayload(map[int]string{0: ""}), wantErr: true},
+		{name: "too few fields", body: []byte("1|2|3"), wantErr: true},
+		{name: "selected axis and extra field unknown", body: testPayload(map[int]string{18: "3"}, "42"), wantUnknown: []int{18, 25}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := layout.Parse(tt.body, model)
+			if tt.wantErr {
+				if !errors.Is(err, ErrPayload) {
+					t.Fatalf("에러 = %v, want ErrPayload", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("에러 = %v, want nil", err)
+			}
+
+			var fields []string
+			for _, e := range state.Meta.ParseErrors {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantErrors) {
+				t.Fatalf("ParseErrors = %+v, want 필드 %v", state.Meta.ParseErrors, tt.wantErrors)
+			}
+			var unknown []int
+			for _, f := range state.Meta.UnknownFields {
+				unknown = append(unknown, f.Index)
+			}
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Fatalf("UnknownFields = %+v, want 위치 %v", state.Meta.UnknownFields, tt.wantUnknown)
+			}
+			// 읽지 못한 값은 0
+			for _, v := range append(append([]float64{}, state.Joint...), state.ToolData...) {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Fatalf("유한하지 않은 값이 상태에 남음: joint=%v tool=%v", state.Joint, state.ToolData)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPayloadLayoutFile(t *testing.T) {
+	layout, err := LoadPayloadLayout("../../layouts/jogrefresh-selected-axis.json")
+	if err != nil {
+		t.Fatalf("레이아웃 로드 실패: %v", err)
+	}
+	if layout.Name() != "jogrefresh-selected-axis" {
+		t.Fatalf("Name() = %q", layout.Name())
+	}
+
+	state, err := layout.Parse(testPayload(map[int]string{18: "3"}), DefaultModel())
+	if err != nil {
+		t.Fatalf("해석 실패: %v", err)
+	}
+	if state.Status.SelectedAxis != 3 || len(state.Meta.UnknownFields) != 0 {
+		t.Fatalf("선택된 축 = %d, UnknownFields = %+v, want 3, 없음", state.Status.SelectedAxis, state.Meta.UnknownFields)
+	}
+	if state.Meta.Layout != "jogrefresh-selected-axis" {
+		t.Fatalf("Meta.Layout = %q", state.Meta.Layout)
+	}
+}
+
+func TestReadPayloadLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "typical", size: len(benchPayload)},
+		{name: "at limit", size: MAX_POOLED_PAYLOAD_BYTES},
+		{name: "over limit", size: MAX_POOLED_PAYLOAD_BYTES + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := readPayload(bytes.NewReader(bytes.Repeat([]byte("1"), tt.size)))
+			if tt.wantErr {
+				if !errors.Is(err, ErrPayload) {
+					t.Fatalf("에러 = %v, want ErrPayload", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("에러 = %v, want nil", err)
+			}
+			if buf.Len() != tt.size {
+				t.Fatalf("읽은 크기 = %d, want %d", buf.Len(), tt.size)
+			}
+			releasePayload(buf)
+		})
+	}
+}
+
+// checkSameAsLegacy 두 방식의 결과가 같은지 확인 (다르면 벤치마크 비교가 의미 없음)
+func checkSameAsLegacy(b *testing.B, body []byte, model *Model, layout *PayloadLayout) {
+	b.Helper()
+	want, err := legacyParseRobotData(body, model)
+	if err != nil {
+		b.Fatal(err)
+	}
+	got, err := layout.Parse(body, model)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Cartesian, want.Cartesian) || !reflect.DeepEqual(got.Joint, want.Joint) ||
+		!reflect.DeepEqual(got.ToolData, want.ToolData) || got.Status != want.Status {
+		b.Fatalf("해석 결과가 다릅니다:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+// ============================================================================
+// 기존 방식 (비교용)
+// ============================================================================
+
+// legacyParseRobotData 레이아웃 도입 전 고정 위치 해석 (strings.Split + fmt.Sscanf)
+func legacyParseRobotData(body []byte, model *Model) (*types.JogState, error) {
+	parts := strings.Split(strings.TrimSpace(string(body)), "|")
+	if len(parts) < 25 {
+		return nil, fmt.Errorf("응답 데이터가 부족합니다: %d개 항목", len(parts))
+	}
+
+	cartesian := make([]float64, 6)
+	for i := 0; i < 6; i++ {
+		if v, err := legacyParseFloat(parts[i]); err == nil {
+			cartesian[i] = v
+		}
+	}
+	joint := make([]float64, 12)
+	for i := 0; i < 12; i++ {
+		if v, err := legacyParseFloat(parts[i+6]); err == nil {
+			joint[i] = v
+		}
+	}
+	toolData := make([]float64, 6)
+	if parts[24] != "" {
+		toolParts := strings.Split(parts[24], ",")
+		for i := 0; i < 6 && i < len(toolParts); i++ {
+			if v, err := legacyParseFloat(toolParts[i]); err == nil {
+				toolData[i] = v
+			}
+		}
+	}
+
+	status := types.JogStatus{SelectedAxis: 1}
+	if v, err := legacyParseFloat(parts[19]); err == nil {
+		status.AxisCount = int(v)
+	}
+	if v, err := legacyParseFloat(parts[20]); err == nil {
+		status.AllowJog = v > 0
+	}
+	if v, err := legacyParseFloat(parts[21]); err == nil {
+		status.JogMode = int(v)
+	}
+	status.JogModeText = model.modeText(status.JogMode)
+	if v, err := legacyParseFloat(parts[22]); err == nil {
+		status.PowerState = int(v)
+	}
+	status.ErrorDesc = strings.TrimSpace(parts[23])
+	status.SelectedAxisText = model.axisText(status.JogMode, status.SelectedAxis)
+
+	return &types.JogState{Cartesian: cartesian, Joint: joint, ToolData: toolData, Status: status}, nil
+}
+
+// legacyParseFloat 기존 parseFloat (fmt.Sscanf)
+func legacyParseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0.0, nil
+	}
+	var v float64
+	_, err := fmt.Sscanf(s, "%f", &v)
+	return v, err
+}
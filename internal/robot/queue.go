@@ -0,0 +1,362 @@
+// ============================================================================
+// internal/robot/queue.go - 컨트롤러 쓰기 명령 직렬화 큐
+// ============================================================================
+// 브라우저의 겹치는 fetch('/api/jog') 요청과 서버 JOG 세션의 반복 전송이
+// 동시에 /wrtpdb에 도달하면, 먼저 보낸 JOG 명령이 중단 명령보다 늦게
+// 도착할 수 있습니다. QueuedController는 로봇당 하나의 작업 고루틴으로
+// 모든 쓰기 명령(JOG, 모드, 축 선택)을 순서대로 전송합니다.
+//
+// 순서 규칙:
+// - 중단 명령(Dir "stop")은 우선 큐로 들어가 대기 중인 명령보다 먼저 전송
+// - 중단 명령이 들어오면 대기 중인 JOG 명령은 전송하지 않고 폐기
+// - Seq가 있는 JOG 명령은 같은 ClientSession의 마지막 중단 Seq 이하이면 폐기
+//   (ClientSession이 없으면 서로 다른 클라이언트가 섞이므로 Seq 순서를 적용하지 않음)
+// - JOG 비활성화도 우선 큐로 전송
+// - 상태 조회(GetRobotData)와 연결 상태는 큐를 거치지 않음
+// - 전체 정지(AllStop)는 장벽을 세워 큐를 비우고 직접 전송한 뒤, 전송 중이던
+//   명령이 끝나면 우선 큐로 중단 명령을 한 번 더 전송
+// - 전체 정지 장벽이 서 있는 동안 들어온 JOG 명령은 폐기
+// ============================================================================
+
+package robot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// 명령 큐 기본값
+const (
+	DEFAULT_COMMAND_QUEUE_SIZE = 64  // 일반 큐 최대 대기 명령 수
+	maxTrackedClientSessions   = 256 // 중단 Seq를 기억하는 클라이언트 세션 수
+)
+
+// 큐 명령 종류
+const (
+	queuedJog = iota
+	queuedMode
+	queuedAxis
+	queuedDisable
+)
+
+// ============================================================================
+// 큐 컨트롤러 (Queued Controller)
+// ============================================================================
+
+// QueueOptions 명령 큐 옵션
+type QueueOptions struct {
+	Size int // 일반 큐 최대 대기 명령 수 (초과 시 COMMAND_QUEUE_FULL)
+}
+
+// queuedCommand 큐에서 대기 중인 쓰기 명령
+type queuedCommand struct {
+	kind   int
+	jog    types.JogCommand
+	mode   string
+	axis   int
+	robot  int
+	result chan queuedResult
+}
+
+// queuedResult 명령 실행 결과
+type queuedResult struct {
+	resp *types.JogResponse
+	err  error
+}
+
+// stopMark 클라이언트 세션별 마지막 중단 명령
+type stopMark struct {
+	seq uint64
+	at  time.Time
+}
+
+// QueueStats 명령 큐 통계
+type QueueStats struct {
+	Pending   int    `json:"pending"`   // 대기 중인 명령 수
+	Executed  uint64 `json:"executed"`  // 전송한 명령 수
+	Discarded uint64 `json:"discarded"` // 중단 명령 때문에 폐기한 JOG 명령 수
+	Rejected  uint64 `json:"rejected"`  // 큐가 가득 차 거부한 명령 수
+}
+
+// QueuedController 쓰기 명령을 하나의 큐로 직렬화하는 Controller 데코레이터
+type QueuedController struct {
+	inner Controller
+	opts  QueueOptions
+
+	mu       sync.Mutex
+	priority []*queuedCommand // 중단/JOG 비활성화 명령
+	normal   []*queuedCommand // 그 외 쓰기 명령
+	stops    map[string]stopMark
+	barrier  int // 진행 중인 전체 정지 수 (0보다 크면 새 JOG 명령 폐기)
+	stats    QueueStats
+	wake     chan struct{}
+}
+
+// NewQueuedController inner 앞에 명령 큐를 두고 작업 고루틴 시작
+func NewQueuedController(inner Controller, opts QueueOptions) *QueuedController {
+	if opts.Size <= 0 {
+		opts.Size = DEFAULT_COMMAND_QUEUE_SIZE
+	}
+	q := &QueuedController{
+		inner: inner,
+		opts:  opts,
+		stops: make(map[string]stopMark),
+		wake:  make(chan struct{}, 1),
+	}
+	go q.run()
+	return q
+}
+
+// SendJogCommand JOG 명령을 큐에 넣고 전송 결과 대기
+func (q *QueuedController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	return q.submit(&queuedCommand{kind: queuedJog, jog: cmd})
+}
+
+// SetJogMode 모드 변경 명령을 큐에 넣고 전송 결과 대기
+func (q *QueuedController) SetJogMode(mode string) (*types.JogResponse, error) {
+	return q.submit(&queuedCommand{kind: queuedMode, mode: mode})
+}
+
+// SetAxis 축 선택 명령을 큐에 넣고 전송 결과 대기
+func (q *QueuedController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	return q.submit(&queuedCommand{kind: queuedAxis, axis: axis, robot: robot})
+}
+
+// DisableJog JOG 비활성화 명령을 우선 큐에 넣고 전송 결과 대기
+func (q *QueuedController) DisableJog() (*types.JogResponse, error) {
+	return q.submit(&queuedCommand{kind: queuedDisable})
+}
+
+// GetRobotData 상태 조회 (큐를 거치지 않음)
+func (q *QueuedController) GetRobotData() (*types.JogState, error) {
+	return q.inner.GetRobotData()
+}
+
+// ConnectionStatus 연결 상태 조회 (큐를 거치지 않음)
+func (q *QueuedController) ConnectionStatus() types.ConnectionStatus {
+	return q.inner.ConnectionStatus()
+}
+
+// Stats 명령 큐 통계 스냅샷
+func (q *QueuedController) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.stats
+	stats.Pending = len(q.priority) + len(q.normal)
+	return stats
+}
+
+// ============================================================================
+// 큐 처리 (Queue Processing)
+// ============================================================================
+
+// submit 명령을 큐에 넣고 결과 대기
+func (q *QueuedController) submit(cmd *queuedCommand) (*types.JogResponse, error) {
+	cmd.result = make(chan queuedResult, 1)
+
+	if rejected := q.enqueue(cmd); rejected != nil {
+		return rejected.resp, rejected.err
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	res := <-cmd.result
+	return res.resp, res.err
+}
+
+// enqueue 순서 규칙을 적용하여 큐에 추가 (즉시 거부되면 결과 반환)
+func (q *QueuedController) enqueue(cmd *queuedCommand) *queuedResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if cmd.kind == queuedJog && cmd.jog.Dir == "stop" {
+		q.markStop(cmd.jog)
+		q.discardPendingJogs("중단 명령으로 대기 중인 JOG 명령 폐기")
+		q.priority = append(q.priority, cmd)
+		return nil
+	}
+	if cmd.kind == queuedDisable {
+		q.priority = append(q.priority, cmd)
+		return nil
+	}
+
+	if cmd.kind == queuedJog && q.barrier > 0 {
+		q.stats.Discarded++
+		res := staleResult(cmd.jog, "전체 정지 중 JOG 명령 무시")
+		return &res
+	}
+	if cmd.kind == queuedJog && q.isStale(cmd.jog) {
+		q.stats.Discarded++
+		res := staleResult(cmd.jog, "중단 명령 이전에 보낸 JOG 명령 무시")
+		return &res
+	}
+
+	if len(q.normal) >= q.opts.Size {
+		q.stats.Rejected++
+		resp, err := rejectedResponse(types.ErrCodeQueueFull, fmt.Sprintf("명령 큐가 가득 찼습니다 (%d개 대기 중)", len(q.normal)))
+		return &queuedResult{resp: resp, err: err}
+	}
+
+	q.normal = append(q.normal, cmd)
+	return nil
+}
+
+// discardPendingJogs 대기 중인 JOG 명령 폐기 - 폐기한 수 반환 (호출자가 잠금 보유)
+// 중단 명령보다 늦게 전송되면 안 되므로 결과를 STALE_COMMAND로 즉시 돌려줍니다.
+func (q *QueuedController) discardPendingJogs(message string) int {
+	discarded := 0
+	kept := q.normal[:0]
+	for _, pending := range q.normal {
+		if pending.kind == queuedJog {
+			pending.result <- staleResult(pending.jog, message)
+			discarded++
+			continue
+		}
+		kept = append(kept, pending)
+	}
+	q.normal = kept
+	q.stats.Discarded += uint64(discarded)
+	return discarded
+}
+
+// Preempt 대기 중인 JOG 명령을 모두 폐기 - 폐기한 수 반환
+// 전송 중인 명령은 기다리지 않으므로, 호출자는 이후 inner로 직접 전송합니다.
+func (q *QueuedController) Preempt() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.discardPendingJogs("전체 정지로 대기 중인 JOG 명령 폐기")
+}
+
+// HoldJogs 전체 정지 장벽을 세우고 대기 중인 JOG 명령 폐기 - 폐기한 수 반환
+// ReleaseJogs를 호출할 때까지 새로 들어오는 JOG 명령은 STALE_COMMAND로 거부됩니다.
+// 중단 명령과 JOG 비활성화는 장벽과 관계없이 전송됩니다.
+func (q *QueuedController) HoldJogs() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.barrier++
+	return q.discardPendingJogs("전체 정지로 대기 중인 JOG 명령 폐기")
+}
+
+// ReleaseJogs HoldJogs로 세운 장벽 해제
+func (q *QueuedController) ReleaseJogs() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.barrier > 0 {
+		q.barrier--
+	}
+}
+
+// markStop 클라이언트 세션의 마지막 중단 Seq 기록 (호출자가 잠금 보유)
+func (q *QueuedController) markStop(cmd types.JogCommand) {
+	if cmd.Seq == 0 || cmd.ClientSession == "" {
+		return
+	}
+	if mark, ok := q.stops[cmd.ClientSession]; ok && mark.seq >= cmd.Seq {
+		return
+	}
+
+	// 오래된 클라이언트 세션 정리
+	if len(q.stops) >= maxTrackedClientSessions {
+		var oldestKey string
+		var oldest time.Time
+		for key, mark := range q.stops {
+			if oldest.IsZero() || mark.at.Before(oldest) {
+				oldestKey, oldest = key, mark.at
+			}
+		}
+		delete(q.stops, oldestKey)
+	}
+	q.stops[cmd.ClientSession] = stopMark{seq: cmd.Seq, at: time.Now()}
+}
+
+// isStale 마지막 중단 명령보다 먼저 보낸 JOG 명령인지 확인 (호출자가 잠금 보유)
+func (q *QueuedController) isStale(cmd types.JogCommand) bool {
+	if cmd.Seq == 0 || cmd.ClientSession == "" {
+		return false
+	}
+	mark, ok := q.stops[cmd.ClientSession]
+	return ok && cmd.Seq <= mark.seq
+}
+
+// next 다음 실행할 명령 (중단 명령 우선) - 없으면 nil
+func (q *QueuedController) next() *queuedCommand {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.priority) > 0 {
+		cmd := q.priority[0]
+		q.priority = q.priority[1:]
+		return cmd
+	}
+	if len(q.normal) > 0 {
+		cmd := q.normal[0]
+		q.normal = q.normal[1:]
+		return cmd
+	}
+	return nil
+}
+
+// run 작업 고루틴 - 큐의 명령을 하나씩 컨트롤러로 전송
+func (q *QueuedController) run() {
+	for {
+		cmd := q.next()
+		if cmd == nil {
+			<-q.wake
+			continue
+		}
+
+		var res queuedResult
+		switch cmd.kind {
+		case queuedJog:
+			res.resp, res.err = q.inner.SendJogCommand(cmd.jog)
+		case queuedMode:
+			res.resp, res.err = q.inner.SetJogMode(cmd.mode)
+		case queuedAxis:
+			res.resp, res.err = q.inner.SetAxis(cmd.axis, cmd.robot)
+		case queuedDisable:
+			res.resp, res.err = q.inner.DisableJog()
+		}
+
+		q.mu.Lock()
+		q.stats.Executed++
+		q.mu.Unlock()
+
+		cmd.result <- res
+	}
+}
+
+// ============================================================================
+// 응답 헬퍼 (Response Helpers)
+// ============================================================================
+
+// staleResult 폐기된 JOG 명령 결과
+func staleResult(cmd types.JogCommand, message string) queuedResult {
+	if cmd.Seq != 0 {
+		message = fmt.Sprintf("%s (seq=%d)", message, cmd.Seq)
+	}
+	logDebug("%s: %s %s", message, cmd.Axis, cmd.Dir)
+	resp, err := rejectedResponse(types.ErrCodeStaleCommand, message)
+	return queuedResult{resp: resp, err: err}
+}
+
+// rejectedResponse 컨트롤러로 전송하지 않고 거부한 명령 응답
+func rejectedResponse(code string, message string) (*types.JogResponse, error) {
+	return &types.JogResponse{
+		Success:   false,
+		Message:   message,
+		Command:   "",
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		ErrorCode: code,
+	}, &CommandError{Code: code, Message: message}
+}
@@ -0,0 +1,291 @@
+// ============================================================================
+// internal/robot/queue_test.go - 명령 큐 순서 규칙 테스트
+// ============================================================================
+// 중단 명령 우선, 중단 Seq 이하 JOG 폐기, Preempt, 전체 정지 장벽을
+// 가짜 컨트롤러로 확인합니다. 가짜 컨트롤러는 JOG 명령 전송을 붙잡아 두어
+// 작업 고루틴이 명령을 전송 중인 상태를 만들 수 있습니다.
+// ============================================================================
+
+package robot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// ============================================================================
+// 가짜 컨트롤러 (Fake Controller)
+// ============================================================================
+
+// fakeController 전송한 명령을 기록하는 Controller 구현
+type fakeController struct {
+	mu    sync.Mutex
+	sent  []string              // 전송 순서 ("jog:<axis>:<dir>", "mode:<mode>", "disable")
+	hold  chan struct{}         // nil이 아니면 중단이 아닌 JOG 명령은 닫힐 때까지 대기
+	enter chan types.JogCommand // nil이 아니면 JOG 명령 전송 시작 알림
+
+	disableErr error // nil이 아니면 DisableJog 실패
+}
+
+func (f *fakeController) record(name string) *types.JogResponse {
+	f.mu.Lock()
+	f.sent = append(f.sent, name)
+	f.mu.Unlock()
+	return &types.JogResponse{Success: true, Message: name}
+}
+
+func (f *fakeController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	if f.enter != nil {
+		f.enter <- cmd
+	}
+	if cmd.Dir != "stop" && f.hold != nil {
+		<-f.hold
+	}
+	return f.record("jog:" + cmd.Axis + ":" + cmd.Dir), nil
+}
+
+func (f *fakeController) SetJogMode(mode string) (*types.JogResponse, error) {
+	return f.record("mode:" + mode), nil
+}
+
+func (f *fakeController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	return f.record("axis"), nil
+}
+
+func (f *fakeController) DisableJog() (*types.JogResponse, error) {
+	if f.disableErr != nil {
+		return rejectedResponse(types.ErrCodeRejected, f.disableErr.Error())
+	}
+	return f.record("disable"), nil
+}
+
+func (f *fakeController) GetRobotData() (*types.JogState, error) {
+	return &types.JogState{}, nil
+}
+
+func (f *fakeController) ConnectionStatus() types.ConnectionStatus {
+	return types.ConnectionStatus{}
+}
+
+// Sent 전송한 명령 목록 스냅샷
+func (f *fakeController) Sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+// ============================================================================
+// 테스트 헬퍼 (Helpers)
+// ============================================================================
+
+// asyncResult 고루틴에서 보낸 명령의 결과
+type asyncResult struct {
+	resp *types.JogResponse
+	err  error
+}
+
+// sendAsync 명령을 고루틴에서 보내고 결과 채널 반환
+func sendAsync(send func() (*types.JogResponse, error)) <-chan asyncResult {
+	ch := make(chan asyncResult, 1)
+	go func() {
+		resp, err := send()
+		ch <- asyncResult{resp, err}
+	}()
+	return ch
+}
+
+// waitFor cond가 참이 될 때까지 대기 (1초 안에 안 되면 실패)
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("시간 초과: %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// recv 결과 수신 (1초 안에 안 오면 실패)
+func recv(t *testing.T, ch <-chan asyncResult) asyncResult {
+	t.Helper()
+	select {
+	case res := <-ch:
+		return res
+	case <-time.After(time.Second):
+		t.Fatal("명령 결과 시간 초과")
+		return asyncResult{}
+	}
+}
+
+// newHeldQueue 첫 JOG 명령("J1")이 전송 중에 멈춰 있는 큐
+// release를 호출하면 붙잡힌 명령이 끝나고 작업 고루틴이 다음 명령으로 넘어갑니다.
+func newHeldQueue(t *testing.T) (q *QueuedController, fake *fakeController, first <-chan asyncResult, release func()) {
+	t.Helper()
+	fake = &fakeController{hold: make(chan struct{}), enter: make(chan types.JogCommand, 16)}
+	q = NewQueuedController(fake, QueueOptions{})
+	first = sendAsync(func() (*types.JogResponse, error) {
+		return q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "positive"})
+	})
+	<-fake.enter
+	var once sync.Once
+	return q, fake, first, func() { once.Do(func() { close(fake.hold) }) }
+}
+
+func assertSent(t *testing.T, fake *fakeController, want ...string) {
+	t.Helper()
+	got := fake.Sent()
+	if len(got) != len(want) {
+		t.Fatalf("전송 명령 = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("전송 명령 = %v, want %v", got, want)
+		}
+	}
+}
+
+// ============================================================================
+// 테스트 (Tests)
+// ============================================================================
+
+func TestQueueStopOvertakesQueuedJogs(t *testing.T) {
+	q, fake, first, release := newHeldQueue(t)
+	defer release()
+
+	jog := sendAsync(func() (*types.JogResponse, error) {
+		return q.SendJogCommand(types.JogCommand{Axis: "J2", Dir: "positive"})
+	})
+	mode := sendAsync(func() (*types.JogResponse, error) { return q.SetJogMode("world") })
+	waitFor(t, "명령 2개 대기", func() bool { return q.Stats().Pending == 2 })
+
+	stop := sendAsync(func() (*types.JogResponse, error) {
+		return q.SendJogCommand(types.JogCommand{Axis: "J2", Dir: "stop"})
+	})
+
+	// 대기 중이던 JOG는 중단 명령이 들어오는 즉시 폐기
+	if res := recv(t, jog); ErrorCode(res.err) != types.ErrCodeStaleCommand {
+		t.Fatalf("대기 중인 JOG 에러 = %v, want %s", res.err, types.ErrCodeStaleCommand)
+	}
+
+	release()
+	for _, ch := range []<-chan asyncResult{first, stop, mode} {
+		if res := recv(t, ch); res.err != nil {
+			t.Fatalf("명령 실패: %v", res.err)
+		}
+	}
+	assertSent(t, fake, "jog:J1:positive", "jog:J2:stop", "mode:world")
+
+	if stats := q.Stats(); stats.Discarded != 1 || stats.Executed != 3 {
+		t.Fatalf("통계 = %+v, want discarded=1 executed=3", stats)
+	}
+}
+
+func TestQueueDropsJogsAtOrBeforeLastStopSeq(t *testing.T) {
+	tests := []struct {
+		name        string
+		stopSession string
+		session     string
+		seq         uint64
+		wantSent    bool
+	}{
+		{name: "lower seq", stopSession: "tab-a", session: "tab-a", seq: 4, wantSent: false},
+		{name: "same seq", stopSession: "tab-a", session: "tab-a", seq: 5, wantSent: false},
+		{name: "higher seq", stopSession: "tab-a", session: "tab-a", seq: 6, wantSent: true},
+		{name: "no seq", stopSession: "tab-a", session: "tab-a", seq: 0, wantSent: true},
+		{name: "other session", stopSession: "tab-a", session: "tab-b", seq: 1, wantSent: true},
+		// ClientSession 없는 Seq는 다른 익명 클라이언트의 JOG를 폐기하지 않음
+		{name: "anonymous stop", stopSession: "", session: "", seq: 1, wantSent: true},
+		{name: "anonymous jog", stopSession: "tab-a", session: "", seq: 1, wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeController{}
+			q := NewQueuedController(fake, QueueOptions{})
+
+			if _, err := q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "stop", Seq: 5, ClientSession: tt.stopSession}); err != nil {
+				t.Fatalf("중단 명령 실패: %v", err)
+			}
+			_, err := q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "positive", Seq: tt.seq, ClientSession: tt.session})
+
+			if tt.wantSent {
+				if err != nil {
+					t.Fatalf("JOG 에러 = %v, want nil", err)
+				}
+				assertSent(t, fake, "jog:J1:stop", "jog:J1:positive")
+				return
+			}
+			if ErrorCode(err) != types.ErrCodeStaleCommand {
+				t.Fatalf("JOG 에러 = %v, want %s", err, types.ErrCodeStaleCommand)
+			}
+			assertSent(t, fake, "jog:J1:stop")
+		})
+	}
+}
+
+func TestQueuePreemptReturnsDiscardedCount(t *testing.T) {
+	tests := []struct {
+		name string
+		jogs int
+	}{
+		{name: "empty", jogs: 0},
+		{name: "one", jogs: 1},
+		{name: "several", jogs: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, fake, first, release := newHeldQueue(t)
+			defer release()
+
+			var jogs []<-chan asyncResult
+			for i := 0; i < tt.jogs; i++ {
+				jogs = append(jogs, sendAsync(func() (*types.JogResponse, error) {
+					return q.SendJogCommand(types.JogCommand{Axis: "J2", Dir: "negative"})
+				}))
+			}
+			mode := sendAsync(func() (*types.JogResponse, error) { return q.SetJogMode("joint") })
+			waitFor(t, "명령 대기", func() bool { return q.Stats().Pending == tt.jogs+1 })
+
+			if got := q.Preempt(); got != tt.jogs {
+				t.Fatalf("Preempt() = %d, want %d", got, tt.jogs)
+			}
+			for _, ch := range jogs {
+				if res := recv(t, ch); ErrorCode(res.err) != types.ErrCodeStaleCommand {
+					t.Fatalf("폐기된 JOG 에러 = %v, want %s", res.err, types.ErrCodeStaleCommand)
+				}
+			}
+
+			// 모드 변경은 폐기하지 않음
+			release()
+			recv(t, first)
+			if res := recv(t, mode); res.err != nil {
+				t.Fatalf("모드 변경 실패: %v", res.err)
+			}
+			assertSent(t, fake, "jog:J1:positive", "mode:joint")
+		})
+	}
+}
+
+func TestQueueHoldJogsRejectsNewJogsUntilRelease(t *testing.T) {
+	fake := &fakeController{}
+	q := NewQueuedController(fake, QueueOptions{})
+
+	q.HoldJogs()
+	if _, err := q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "positive"}); ErrorCode(err) != types.ErrCodeStaleCommand {
+		t.Fatalf("장벽 중 JOG 에러 = %v, want %s", err, types.ErrCodeStaleCommand)
+	}
+	if _, err := q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "stop"}); err != nil {
+		t.Fatalf("장벽 중 중단 명령 실패: %v", err)
+	}
+
+	q.ReleaseJogs()
+	if _, err := q.SendJogCommand(types.JogCommand{Axis: "J1", Dir: "positive"}); err != nil {
+		t.Fatalf("장벽 해제 후 JOG 실패: %v", err)
+	}
+	assertSent(t, fake, "jog:J1:stop", "jog:J1:positive")
+}
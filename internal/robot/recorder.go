@@ -0,0 +1,463 @@
+// ============================================================================
+// internal/robot/recorder.go - 궤적 기록 (CSV / JSON Lines)
+// ============================================================================
+// 이름을 붙인 기록을 API로 시작/중단하고, 기록 중에는 상태 조회마다의
+// 조인트/카르테시안/툴 값과 보낸 명령을 파일로 바로 기록합니다.
+// 공정 엔지니어가 MonitorRobotPosition 터미널 출력을 복사하는 대신
+// 파일을 내려받아 오프라인으로 분석할 수 있습니다.
+//
+// 기록 규칙:
+// - 상태와 명령은 이벤트 버스(state, command 이벤트)에서 받음
+//   (연속 JOG 세션은 반복 전송 대신 세션 시작/중단이 기록됨)
+// - 이벤트마다 파일에 바로 기록 (서버가 종료되어도 마지막 이벤트까지 남음)
+// - 같은 이름의 파일이 이미 있으면 시작하지 않음 (덮어쓰지 않음)
+// - 진행 중인 기록은 삭제할 수 없음
+// ============================================================================
+
+package robot
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 기록 파일 형식
+const (
+	RecordingCSV   = "csv"
+	RecordingJSONL = "jsonl"
+)
+
+// 궤적 기록 기본값
+const (
+	DEFAULT_RECORDING_MAX_ACTIVE = 4
+	RECORDING_TOOL_FIELDS        = 6 // jogrefresh.asp 툴 데이터 수
+)
+
+// 궤적 기록 오류
+var (
+	ErrInvalidRecording   = errors.New("잘못된 기록 요청")
+	ErrRecordingExists    = errors.New("같은 이름의 기록이 이미 있습니다")
+	ErrRecordingNotFound  = errors.New("기록을 찾을 수 없습니다")
+	ErrRecordingActive    = errors.New("진행 중인 기록입니다")
+	ErrTooManyRecordings  = errors.New("동시에 진행할 수 있는 기록 수를 넘었습니다")
+	recordingNamePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	recordingFileSuffixes = []string{"." + RecordingCSV, "." + RecordingJSONL}
+)
+
+// recording 진행 중인 기록 하나
+type recording struct {
+	info    types.RecordingInfo
+	startID uint64 // 이 ID 이후의 이벤트만 기록
+	file    *os.File
+	buf     *bufio.Writer
+	csv     *csv.Writer // CSV 형식일 때만
+}
+
+// recordLine JSON Lines 한 줄
+type recordLine struct {
+	Time      string              `json:"time"`
+	Type      string              `json:"type"` // "state", "command"
+	Seq       uint64              `json:"seq,omitempty"`
+	Joint     []float64           `json:"joint,omitempty"`
+	Cartesian []float64           `json:"cartesian,omitempty"`
+	Tool      []float64           `json:"tool,omitempty"`
+	Command   *types.CommandEvent `json:"command,omitempty"`
+}
+
+// Recorder 궤적 기록 관리자
+type Recorder struct {
+	dir       string
+	maxActive int
+	model     *Model
+	bus       *EventBus
+
+	mu       sync.Mutex
+	active   map[string]*recording
+	finished map[string]types.RecordingInfo // 이번 실행에서 끝난 기록 (개수 정보 보존)
+}
+
+// NewRecorder 궤적 기록 관리자 생성 (model이 nil이면 내장 모델)
+func NewRecorder(model *Model, bus *EventBus, cfg types.RecordingConfig) *Recorder {
+	if model == nil {
+		model = DefaultModel()
+	}
+	maxActive := cfg.MaxActive
+	if maxActive <= 0 {
+		maxActive = DEFAULT_RECORDING_MAX_ACTIVE
+	}
+	return &Recorder{
+		dir:       cfg.Directory,
+		maxActive: maxActive,
+		model:     model,
+		bus:       bus,
+		active:    make(map[string]*recording),
+		finished:  make(map[string]types.RecordingInfo),
+	}
+}
+
+// Dir 기록 파일 디렉터리
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// ActiveCount 진행 중인 기록 수
+func (r *Recorder) ActiveCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.active)
+}
+
+// Run 이벤트 버스 구독 → 진행 중인 기록에 기록 (반환하지 않음 - 고루틴으로 실행)
+// 디스크 쓰기가 밀려 구독이 끊기면 놓친 이벤트부터 다시 구독합니다.
+func (r *Recorder) Run() {
+	lastID := r.bus.LastID()
+	for {
+		replay, _, events, unsubscribe := r.bus.Subscribe(lastID)
+		for _, event := range replay {
+			r.write(event)
+			lastID = event.ID
+		}
+		for event := range events {
+			r.write(event)
+			lastID = event.ID
+		}
+		unsubscribe()
+		logDebug("기록 구독이 밀려 다시 구독합니다 (마지막 이벤트 %d)", lastID)
+	}
+}
+
+// Start 기록 시작 - 파일을 만들고 헤더 기록
+func (r *Recorder) Start(name, format, startedBy string) (types.RecordingInfo, error) {
+	if !recordingNamePattern.MatchString(name) {
+		return types.RecordingInfo{}, fmt.Errorf("%w: 이름은 영문/숫자로 시작하고 영문, 숫자, '.', '-', '_'만 사용할 수 있습니다 (최대 64자): %q", ErrInvalidRecording, name)
+	}
+	if format == "" {
+		format = RecordingCSV
+	}
+	if format != RecordingCSV && format != RecordingJSONL {
+		return types.RecordingInfo{}, fmt.Errorf("%w: format은 csv 또는 jsonl이어야 합니다 (값: %q)", ErrInvalidRecording, format)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.active) >= r.maxActive {
+		return types.RecordingInfo{}, fmt.Errorf("%w (최대 %d개)", ErrTooManyRecordings, r.maxActive)
+	}
+	if _, ok := r.active[name]; ok {
+		return types.RecordingInfo{}, fmt.Errorf("%w: %s", ErrRecordingExists, name)
+	}
+	if _, _, err := r.findFile(name); err == nil {
+		return types.RecordingInfo{}, fmt.Errorf("%w: %s", ErrRecordingExists, name)
+	}
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return types.RecordingInfo{}, fmt.Errorf("기록 디렉터리 생성 실패: %w", err)
+	}
+	fileName := name + "." + format
+	file, err := os.OpenFile(filepath.Join(r.dir, fileName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return types.RecordingInfo{}, fmt.Errorf("%w: %s", ErrRecordingExists, fileName)
+	}
+	if err != nil {
+		return types.RecordingInfo{}, fmt.Errorf("기록 파일 생성 실패: %w", err)
+	}
+
+	rec := &recording{
+		info: types.RecordingInfo{
+			Name:      name,
+			Format:    format,
+			File:      fileName,
+			Active:    true,
+			StartedBy: startedBy,
+			StartedAt: time.Now().Format(time.RFC3339Nano),
+		},
+		startID: r.bus.LastID(),
+		file:    file,
+		buf:     bufio.NewWriter(file),
+	}
+	if format == RecordingCSV {
+		rec.csv = csv.NewWriter(rec.buf)
+		rec.csv.Write(r.csvHeader())
+	}
+	if err := rec.flush(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return types.RecordingInfo{}, fmt.Errorf("기록 파일 쓰기 실패: %w", err)
+	}
+
+	r.active[name] = rec
+	delete(r.finished, name)
+	logInfo("⏺️  궤적 기록 시작: %s (%s, 요청자=%s)", fileName, format, startedBy)
+	return rec.info, nil
+}
+
+// Stop 기록 중단 - 파일을 닫고 최종 정보 반환
+func (r *Recorder) Stop(name string) (types.RecordingInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.active[name]
+	if !ok {
+		return types.RecordingInfo{}, fmt.Errorf("%w: 진행 중인 기록이 아닙니다: %s", ErrRecordingNotFound, name)
+	}
+	info := r.finishLocked(rec, nil)
+	logInfo("⏹️  궤적 기록 중단: %s (상태 %d개, 명령 %d개)", info.File, info.States, info.Commands)
+	return info, nil
+}
+
+// finishLocked 기록 종료 처리 (r.mu 보유 상태에서 호출)
+func (r *Recorder) finishLocked(rec *recording, writeErr error) types.RecordingInfo {
+	err := rec.flush()
+	if closeErr := rec.file.Close(); err == nil {
+		err = closeErr
+	}
+	if writeErr != nil {
+		err = writeErr
+	}
+
+	rec.info.Active = false
+	rec.info.StoppedAt = time.Now().Format(time.RFC3339Nano)
+	if err != nil {
+		rec.info.Error = err.Error()
+	}
+	if stat, statErr := os.Stat(filepath.Join(r.dir, rec.info.File)); statErr == nil {
+		rec.info.SizeBytes = stat.Size()
+	}
+
+	delete(r.active, rec.info.Name)
+	r.finished[rec.info.Name] = rec.info
+	return rec.info
+}
+
+// List 진행 중인 기록과 디렉터리의 기록 파일 (최신순)
+func (r *Recorder) List() ([]types.RecordingInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var infos []types.RecordingInfo
+	modTimes := make(map[string]time.Time)
+	for _, entry := range entries {
+		name, format, ok := splitRecordingFile(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		info := types.RecordingInfo{Name: name, Format: format, File: entry.Name()}
+		if rec, ok := r.active[name]; ok && rec.info.File == entry.Name() {
+			info = rec.info
+		} else if done, ok := r.finished[name]; ok && done.File == entry.Name() {
+			info = done
+		}
+		info.SizeBytes = stat.Size()
+		infos = append(infos, info)
+		modTimes[entry.Name()] = stat.ModTime()
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return modTimes[infos[i].File].After(modTimes[infos[j].File])
+	})
+	return infos, nil
+}
+
+// Open 기록 파일 열기 (다운로드용 - 호출한 쪽에서 닫아야 함)
+// 진행 중인 기록은 지금까지 기록된 부분까지 읽힙니다.
+func (r *Recorder) Open(name string) (*os.File, types.RecordingInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fileName, format, err := r.findFile(name)
+	if err != nil {
+		return nil, types.RecordingInfo{}, err
+	}
+	if rec, ok := r.active[strings.TrimSuffix(fileName, "."+format)]; ok {
+		rec.flush()
+	}
+	file, err := os.Open(filepath.Join(r.dir, fileName))
+	if err != nil {
+		return nil, types.RecordingInfo{}, err
+	}
+	return file, types.RecordingInfo{Name: strings.TrimSuffix(fileName, "."+format), Format: format, File: fileName}, nil
+}
+
+// Delete 기록 파일 삭제 (진행 중이면 거부)
+func (r *Recorder) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fileName, format, err := r.findFile(name)
+	if err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(fileName, "."+format)
+	if _, ok := r.active[base]; ok {
+		return fmt.Errorf("%w: 먼저 중단해야 합니다: %s", ErrRecordingActive, base)
+	}
+	if err := os.Remove(filepath.Join(r.dir, fileName)); err != nil {
+		return err
+	}
+	delete(r.finished, base)
+	logInfo("🗑️  궤적 기록 삭제: %s", fileName)
+	return nil
+}
+
+// findFile 이름(확장자 생략 가능)으로 기록 파일 찾기 - 파일 이름과 형식 반환
+func (r *Recorder) findFile(name string) (string, string, error) {
+	base, format, hasExt := splitRecordingFile(name)
+	if !hasExt {
+		base = name
+	}
+	if !recordingNamePattern.MatchString(base) {
+		return "", "", fmt.Errorf("%w: 잘못된 이름: %q", ErrInvalidRecording, name)
+	}
+
+	candidates := []string{RecordingCSV, RecordingJSONL}
+	if hasExt {
+		candidates = []string{format}
+	}
+	for _, f := range candidates {
+		fileName := base + "." + f
+		if stat, err := os.Stat(filepath.Join(r.dir, fileName)); err == nil && !stat.IsDir() {
+			return fileName, f, nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrRecordingNotFound, name)
+}
+
+// splitRecordingFile 파일 이름을 기록 이름과 형식으로 분리
+func splitRecordingFile(fileName string) (name string, format string, ok bool) {
+	for _, suffix := range recordingFileSuffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return strings.TrimSuffix(fileName, suffix), suffix[1:], true
+		}
+	}
+	return "", "", false
+}
+
+// ============================================================================
+// 기록 쓰기 (Writing)
+// ============================================================================
+
+// write 이벤트 하나를 진행 중인 모든 기록에 기록
+func (r *Recorder) write(event types.RobotEvent) {
+	if event.Type != EventState && event.Type != EventCommand {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rec := range r.active {
+		if event.ID <= rec.startID {
+			continue
+		}
+		var err error
+		if rec.csv != nil {
+			rec.csv.Write(r.csvRow(event))
+			err = rec.flush()
+		} else {
+			err = r.writeJSONL(rec, event)
+		}
+		if err != nil {
+			info := r.finishLocked(rec, err)
+			logInfo("❌ 궤적 기록 쓰기 실패로 중단: %s (%v)", info.File, err)
+			continue
+		}
+
+		if event.Type == EventState {
+			rec.info.States++
+		} else {
+			rec.info.Commands++
+		}
+	}
+}
+
+// writeJSONL JSON Lines 한 줄 기록
+func (r *Recorder) writeJSONL(rec *recording, event types.RobotEvent) error {
+	line := recordLine{Time: event.Time, Type: event.Type}
+	switch data := event.Data.(type) {
+	case *types.JogState:
+		line.Seq = data.Meta.Seq
+		line.Joint = data.Joint[:min(len(r.model.info.Joints), len(data.Joint))]
+		line.Cartesian = data.Cartesian[:min(len(r.model.info.Cartesian), len(data.Cartesian))]
+		line.Tool = data.ToolData
+	case types.CommandEvent:
+		line.Command = &data
+	}
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	rec.buf.Write(encoded)
+	rec.buf.WriteByte('\n')
+	return rec.flush()
+}
+
+// csvHeader CSV 헤더 - 상태 열(모델 축, 툴)과 명령 열
+func (r *Recorder) csvHeader() []string {
+	header := []string{"time", "type", "seq"}
+	for _, axis := range r.model.axisRefs() {
+		header = append(header, axis.name)
+	}
+	for i := 1; i <= RECORDING_TOOL_FIELDS; i++ {
+		header = append(header, "tool"+strconv.Itoa(i))
+	}
+	return append(header, "action", "request", "success", "message", "error_code")
+}
+
+// csvRow 이벤트 하나를 CSV 행으로 변환 (해당하지 않는 열은 빈 값)
+func (r *Recorder) csvRow(event types.RobotEvent) []string {
+	axes := r.model.axisRefs()
+	row := make([]string, 3+len(axes)+RECORDING_TOOL_FIELDS+5)
+	row[0], row[1] = event.Time, event.Type
+
+	switch data := event.Data.(type) {
+	case *types.JogState:
+		row[2] = strconv.FormatUint(data.Meta.Seq, 10)
+		for i, axis := range axes {
+			row[3+i] = strconv.FormatFloat(axis.value(data), 'f', -1, 64)
+		}
+		for i := 0; i < RECORDING_TOOL_FIELDS; i++ {
+			row[3+len(axes)+i] = strconv.FormatFloat(getSafeValue(data.ToolData, i), 'f', -1, 64)
+		}
+	case types.CommandEvent:
+		request, _ := json.Marshal(data.Request)
+		cmd := row[3+len(axes)+RECORDING_TOOL_FIELDS:]
+		cmd[0], cmd[1], cmd[2], cmd[3], cmd[4] = data.Action, string(request), strconv.FormatBool(data.Success), data.Message, data.ErrorCode
+	}
+	return row
+}
+
+// flush 버퍼를 파일로 기록
+func (rec *recording) flush() error {
+	if rec.csv != nil {
+		rec.csv.Flush()
+		if err := rec.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return rec.buf.Flush()
+}
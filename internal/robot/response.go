@@ -0,0 +1,213 @@
+// ============================================================================
+// internal/robot/response.go - 컨트롤러 응답 해석
+// ============================================================================
+// /wrtpdb 전송 결과를 HTTP 상태, 리다이렉트 대상, 본문 오류 문구로 판정하여
+// JogResponse.ErrorCode로 구분되는 실패로 변환합니다.
+//
+// 판정 순서:
+// - 전송 실패: 타임아웃 / 연결 실패
+// - 3xx: dbfunctions.asp로의 리다이렉트만 성공 (쿼리의 오류 메시지 확인)
+// - 401/403, 로그인 페이지: 인증 필요
+// - 그 외 4xx/5xx: HTTP 오류
+// - 2xx 본문의 오류 요소(id/class가 ErrMsg, error 등): 컨트롤러 거부
+//
+// 본문의 일반 단어("error", "log in")는 스크립트나 표 제목에도 나오므로
+// 판정에 쓰지 않고, 컨트롤러가 오류를 표시하는 요소만 확인합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 응답 본문 최대 읽기 크기
+const maxResponseBody = 64 * 1024
+
+// 오류 요소에서 가져오는 메시지 최대 길이 (문자 수)
+const maxControllerErrorRunes = 200
+
+// 리다이렉트 쿼리에서 오류 메시지를 담는 파라미터 이름
+var controllerErrorParams = []string{"ErrMsg", "Error", "error", "err"}
+
+var (
+	// htmlTagPattern 오류 요소 내용에서 HTML 태그 제거용
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+	// controllerErrorPattern 컨트롤러 오류 요소 (예: <span id="ErrMsg">Jog not enabled</span>)
+	controllerErrorPattern = regexp.MustCompile(`(?i)<([a-z][a-z0-9]*)\s[^>]*\b(?:id|class)\s*=\s*["']?(?:ErrMsg|error|err)["'\s>][^>]*>`)
+	// loginPagePattern 로그인 페이지 본문 판별 (비밀번호 입력란 또는 login.asp로 전송하는 폼)
+	loginPagePattern = regexp.MustCompile(`(?i)(<input[^>]*\btype\s*=\s*["']?password|\baction\s*=\s*["']?[^"'>]*login\.asp)`)
+	// loginPathPattern 로그인 페이지 리다이렉트 경로 판별
+	loginPathPattern = regexp.MustCompile(`(?i)login`)
+)
+
+// CommandError 컨트롤러 명령 실패 (ErrorCode로 원인 구분)
+type CommandError struct {
+	Code       string // types.ErrCode* 값
+	StatusCode int    // 컨트롤러 HTTP 상태 (없으면 0)
+	Message    string
+	Err        error // 원인 에러 (전송 실패 시)
+}
+
+// Error error 인터페이스 구현
+func (e *CommandError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap 원인 에러 반환
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode err에서 ErrorCode 추출 (CommandError가 아니면 빈 문자열)
+func ErrorCode(err error) string {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code
+	}
+	return ""
+}
+
+// classifyTransportError 전송 실패를 타임아웃/연결 실패로 구분
+func classifyTransportError(err error) *CommandError {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &CommandError{Code: types.ErrCodeTimeout, Message: "로봇 응답 시간 초과", Err: err}
+	}
+	return &CommandError{Code: types.ErrCodeUnreachable, Message: "로봇 통신 실패", Err: err}
+}
+
+// interpretCommandResponse /wrtpdb 응답을 판정 (성공이면 nil)
+func (c *HTTPController) interpretCommandResponse(resp *http.Response) *CommandError {
+	status := resp.StatusCode
+
+	switch {
+	case status >= 300 && status < 400:
+		return c.interpretRedirect(resp)
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &CommandError{Code: types.ErrCodeAuthRequired, StatusCode: status, Message: "컨트롤러 인증 필요"}
+	case status >= 400:
+		msg := fmt.Sprintf("컨트롤러 HTTP 오류: %s", resp.Status)
+		if text := extractControllerError(readBody(resp.Body)); text != "" {
+			msg += " (" + text + ")"
+		}
+		return &CommandError{Code: types.ErrCodeHTTPStatus, StatusCode: status, Message: msg}
+	}
+
+	// 2xx: 리다이렉트 없이 본문을 돌려준 경우 본문으로 판정
+	return interpretBody(status, readBody(resp.Body))
+}
+
+// interpretRedirect 리다이렉트 대상 해석
+// FollowRedirect 옵션이 켜져 있으면 dbfunctions.asp 결과 페이지 본문도 확인합니다.
+func (c *HTTPController) interpretRedirect(resp *http.Response) *CommandError {
+	status := resp.StatusCode
+	loc, err := resp.Location()
+	if err != nil {
+		return &CommandError{Code: types.ErrCodeUnexpectedRedirect, StatusCode: status, Message: "리다이렉트 대상 없음"}
+	}
+
+	if !strings.EqualFold(loc.Path, c.opts.RedirectPath) {
+		if loginPathPattern.MatchString(loc.Path) {
+			return &CommandError{Code: types.ErrCodeAuthRequired, StatusCode: status, Message: "로그인 페이지로 리다이렉트: " + loc.Path}
+		}
+		return &CommandError{Code: types.ErrCodeUnexpectedRedirect, StatusCode: status, Message: "예상하지 못한 리다이렉트: " + loc.String()}
+	}
+
+	// 컨트롤러가 쿼리로 전달한 오류 메시지
+	if msg := redirectErrorMessage(loc.Query()); msg != "" {
+		return &CommandError{Code: types.ErrCodeRejected, StatusCode: status, Message: "컨트롤러 거부: " + msg}
+	}
+
+	if !c.opts.FollowRedirect {
+		return nil
+	}
+
+	// 결과 페이지까지 확인 (추가 왕복 1회)
+	page, err := c.client.Get(loc.String())
+	if err != nil {
+		return classifyTransportError(err)
+	}
+	defer page.Body.Close()
+	if page.StatusCode >= 400 {
+		return &CommandError{Code: types.ErrCodeHTTPStatus, StatusCode: page.StatusCode, Message: "결과 페이지 오류: " + page.Status}
+	}
+	return interpretBody(page.StatusCode, readBody(page.Body))
+}
+
+// interpretBody 응답 본문에서 로그인 페이지나 오류 문구 검사
+func interpretBody(status int, body string) *CommandError {
+	if loginPagePattern.MatchString(body) {
+		return &CommandError{Code: types.ErrCodeAuthRequired, StatusCode: status, Message: "컨트롤러 로그인 페이지 응답"}
+	}
+	if text := extractControllerError(body); text != "" {
+		return &CommandError{Code: types.ErrCodeRejected, StatusCode: status, Message: "컨트롤러 거부: " + text}
+	}
+	return nil
+}
+
+// redirectErrorMessage 리다이렉트 쿼리에서 오류 메시지 추출
+func redirectErrorMessage(query url.Values) string {
+	for _, name := range controllerErrorParams {
+		if msg := strings.TrimSpace(query.Get(name)); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// extractControllerError 본문에서 첫 번째 비어 있지 않은 오류 요소 내용 추출 (없으면 빈 문자열)
+// 메시지와 로그가 페이지 전체로 커지지 않도록 maxControllerErrorRunes에서 자릅니다.
+func extractControllerError(body string) string {
+	for _, loc := range controllerErrorPattern.FindAllStringSubmatchIndex(body, -1) {
+		content := elementContent(body[loc[1]:], body[loc[2]:loc[3]])
+		text := strings.Join(strings.Fields(htmlTagPattern.ReplaceAllString(content, " ")), " ")
+		if text == "" {
+			continue
+		}
+		if runes := []rune(text); len(runes) > maxControllerErrorRunes {
+			text = string(runes[:maxControllerErrorRunes]) + "…"
+		}
+		return text
+	}
+	return ""
+}
+
+// elementContent 여는 태그 뒤의 요소 내용 - 같은 이름의 닫는 태그까지
+// 닫는 태그가 없으면 다음 태그('<') 앞까지만 사용합니다.
+func elementContent(rest string, name string) string {
+	for i := 0; ; {
+		j := strings.Index(rest[i:], "</")
+		if j < 0 {
+			break
+		}
+		i += j
+		tag := rest[i+2:]
+		if len(tag) > len(name) && strings.EqualFold(tag[:len(name)], name) && (tag[len(name)] == '>' || tag[len(name)] == ' ') {
+			return rest[:i]
+		}
+		i += 2
+	}
+	if end := strings.IndexByte(rest, '<'); end >= 0 {
+		return rest[:end]
+	}
+	return rest
+}
+
+// readBody 응답 본문을 최대 maxResponseBody까지 읽기
+func readBody(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, maxResponseBody))
+	return string(data)
+}
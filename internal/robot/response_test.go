@@ -0,0 +1,136 @@
+// ============================================================================
+// internal/robot/response_test.go - 컨트롤러 응답 해석 테스트
+// ============================================================================
+// /wrtpdb 응답의 상태 코드, 리다이렉트 대상, 본문 오류 요소를 ErrorCode로
+// 구분하는지 확인합니다. 스크립트나 표 제목의 "error", "log in" 같은 일반
+// 단어는 실패로 판정하지 않아야 합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// newTestResponse 상태 코드, Location, 본문으로 응답 생성
+func newTestResponse(status int, location string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	if location != "" {
+		resp.Header.Set("Location", location)
+	}
+	return resp
+}
+
+func TestInterpretCommandResponse(t *testing.T) {
+	c := NewHTTPController("http://ctrl", nil, ControllerOptions{})
+	redirect := "http://ctrl" + ROBOT_REDIRECT
+
+	tests := []struct {
+		name        string
+		status      int
+		location    string
+		body        string
+		wantCode    string // 빈 문자열이면 성공
+		wantMessage string // 메시지에 포함되어야 하는 문구
+	}{
+		{name: "redirect to result page", status: http.StatusFound, location: redirect},
+		{name: "redirect with ErrMsg", status: http.StatusFound, location: redirect + "?ErrMsg=Jog+not+enabled",
+			wantCode: types.ErrCodeRejected, wantMessage: "Jog not enabled"},
+		{name: "redirect to login", status: http.StatusFound, location: "http://ctrl/ROMDISK/web/login.asp",
+			wantCode: types.ErrCodeAuthRequired},
+		{name: "redirect elsewhere", status: http.StatusFound, location: "http://ctrl/index.asp",
+			wantCode: types.ErrCodeUnexpectedRedirect},
+		{name: "401", status: http.StatusUnauthorized, wantCode: types.ErrCodeAuthRequired},
+		{name: "500", status: http.StatusInternalServerError, body: "<html>boom</html>", wantCode: types.ErrCodeHTTPStatus},
+		{name: "500 with ErrMsg", status: http.StatusInternalServerError, body: `<span id="ErrMsg">PID write failed</span>`,
+			wantCode: types.ErrCodeHTTPStatus, wantMessage: "PID write failed"},
+		{name: "body with ErrMsg span", status: http.StatusOK,
+			body:     `<html><body><span id="ErrMsg">Error: <b>Jog</b> not enabled</span><p>ok</p></body></html>`,
+			wantCode: types.ErrCodeRejected, wantMessage: "Error: Jog not enabled"},
+		{name: "body with error class", status: http.StatusOK, body: `<div class="error">Invalid joint 9</div>`,
+			wantCode: types.ErrCodeRejected, wantMessage: "Invalid joint 9"},
+		{name: "login form", status: http.StatusOK,
+			body:     `<form action="/ROMDISK/web/login.asp" method="post"><input name="user"><input type="password" name="pw"></form>`,
+			wantCode: types.ErrCodeAuthRequired},
+		{name: "error word in script", status: http.StatusOK,
+			body: `<script>if (error) { alert("failed: invalid"); }</script><table><th>Error</th><th>Status</th></table>`},
+		{name: "log in text", status: http.StatusOK, body: `<p>Please log in again later.</p><a href="logout.asp">Logout</a>`},
+		{name: "empty error element", status: http.StatusOK, body: `<span id="ErrMsg"></span>OK`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdErr := c.interpretCommandResponse(newTestResponse(tt.status, tt.location, tt.body))
+			if tt.wantCode == "" {
+				if cmdErr != nil {
+					t.Fatalf("에러 = %v, want nil", cmdErr)
+				}
+				return
+			}
+			if cmdErr == nil || cmdErr.Code != tt.wantCode {
+				t.Fatalf("에러 = %v, want %s", cmdErr, tt.wantCode)
+			}
+			if !strings.Contains(cmdErr.Message, tt.wantMessage) {
+				t.Fatalf("메시지 = %q, want %q 포함", cmdErr.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestInterpretRedirectFollowsResultPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			io.WriteString(w, `<html><body><span id="ErrMsg">Error: Jog not enabled</span></body></html>`)
+			return
+		}
+		io.WriteString(w, `<html><script>var error = null;</script><body>OK</body></html>`)
+	}))
+	defer srv.Close()
+
+	c := NewHTTPController(srv.URL, nil, ControllerOptions{FollowRedirect: true})
+
+	if cmdErr := c.interpretCommandResponse(newTestResponse(http.StatusFound, srv.URL+ROBOT_REDIRECT, "")); cmdErr != nil {
+		t.Fatalf("정상 결과 페이지 에러 = %v, want nil", cmdErr)
+	}
+	cmdErr := c.interpretCommandResponse(newTestResponse(http.StatusFound, srv.URL+ROBOT_REDIRECT+"?fail=1", ""))
+	if cmdErr == nil || cmdErr.Code != types.ErrCodeRejected {
+		t.Fatalf("오류 결과 페이지 에러 = %v, want %s", cmdErr, types.ErrCodeRejected)
+	}
+}
+
+func TestExtractControllerError(t *testing.T) {
+	long := strings.Repeat("가", maxControllerErrorRunes+50)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "nested tags", body: `<span id="ErrMsg">Jog <b>not</b> enabled</span>`, want: "Jog not enabled"},
+		{name: "unclosed element stops at next tag", body: `<span id="ErrMsg">Jog not enabled<table><tr><td>rest of page</td></tr></table>`,
+			want: "Jog not enabled"},
+		{name: "long message capped", body: `<span id="ErrMsg">` + long + `</span>`,
+			want: string([]rune(long)[:maxControllerErrorRunes]) + "…"},
+		{name: "first non-empty element", body: `<p class="err"> </p><p class="err">second</p>`, want: "second"},
+		{name: "similar id ignored", body: `<span id="errors">x</span>`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractControllerError(tt.body); got != tt.want {
+				t.Fatalf("extractControllerError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// ============================================================================
+// internal/robot/watchdog.go - 상태 데이터 감시 (멈춘 응답, 늦은 조회)
+// ============================================================================
+// 컨트롤러가 로봇이 움직이거나 오류가 난 동안에도 캐시된 jogrefresh.asp
+// 응답을 몇 초씩 그대로 돌려주는 경우가 있습니다. 화면은 멈춘 위치를 보여
+// 주고 소프트 리밋도 그 위치로 검사하게 됩니다. StaleWatchdog은 원본 응답
+// 해시(StateMeta.Fingerprint)와 조회 간격을 보고 상태를 믿을 수 없으면
+// StateMeta.Stale로 표시하고 stale 이벤트를 게시합니다.
+//
+// 판정 규칙 (상태 브로커가 성공한 조회마다 Check 호출):
+// - frozen: JOG 명령이 성공한 뒤 응답이 frozen_ms 동안 한 번도 바뀌지 않음
+//   → 응답이 바뀌거나, 마지막 JOG 명령 뒤 jog_window_ms가 지나면 해제
+// - late_poll: 직전 성공 조회와의 간격이 폴링 주기 + late_poll_ms를 넘음
+//   → 다음 조회가 제때 오면 해제
+//
+// block_jog가 켜져 있으면 StaleGuardController가 stale인 동안(또는 조회가
+// 지금 늦고 있으면) JOG 명령을 STALE_STATE_DATA로 거부합니다. 중단 명령은
+// 항상 전달합니다.
+// ============================================================================
+
+package robot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/logging"
+	"github.com/nir414/go-virtual-pendant/internal/types"
+)
+
+// 감시 결과 (StateMeta.StaleReason, StaleEvent.Reason)
+const (
+	StaleFrozen   = "frozen"
+	StaleLatePoll = "late_poll"
+)
+
+// 감시 기본값
+const (
+	DEFAULT_WATCHDOG_FROZEN     = time.Second
+	DEFAULT_WATCHDOG_JOG_WINDOW = 2 * time.Second
+	DEFAULT_WATCHDOG_LATE_POLL  = time.Second
+)
+
+// StaleWatchdog 상태 데이터 감시기
+type StaleWatchdog struct {
+	frozen    time.Duration
+	jogWindow time.Duration
+	latePoll  time.Duration
+	block     bool
+	bus       *EventBus // nil이면 이벤트를 게시하지 않음
+
+	mu          sync.Mutex
+	fingerprint string
+	polledAt    time.Time     // 마지막 성공 조회
+	expected    time.Duration // 마지막 성공 조회 때의 폴링 주기
+	jogAt       time.Time     // 마지막으로 성공한 JOG 명령
+	pendingAt   time.Time     // 응답이 바뀐 뒤 처음 성공한 JOG 명령 (바뀌면 초기화)
+	reason      string        // 현재 판정 (빈 값이면 정상)
+}
+
+// NewStaleWatchdog 감시기 생성 (0인 설정은 기본값)
+func NewStaleWatchdog(cfg types.WatchdogConfig, bus *EventBus) *StaleWatchdog {
+	w := &StaleWatchdog{
+		frozen:    time.Duration(cfg.FrozenMs) * time.Millisecond,
+		jogWindow: time.Duration(cfg.JogWindowMs) * time.Millisecond,
+		latePoll:  time.Duration(cfg.LatePollMs) * time.Millisecond,
+		block:     cfg.BlockJog,
+		bus:       bus,
+	}
+	if w.frozen <= 0 {
+		w.frozen = DEFAULT_WATCHDOG_FROZEN
+	}
+	if w.jogWindow <= 0 {
+		w.jogWindow = DEFAULT_WATCHDOG_JOG_WINDOW
+	}
+	if w.latePoll <= 0 {
+		w.latePoll = DEFAULT_WATCHDOG_LATE_POLL
+	}
+	return w
+}
+
+// NoteJog 성공한 JOG 명령 기록 (이후 응답이 바뀌어야 함)
+func (w *StaleWatchdog) NoteJog(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.jogAt = now
+	if w.pendingAt.IsZero() {
+		w.pendingAt = now
+	}
+}
+
+// Check 성공한 조회 결과 검사 - state.Meta.Stale 설정, 판정이 바뀌면 stale 이벤트 게시
+// expected는 이번 조회까지 기다린 폴링 주기입니다.
+func (w *StaleWatchdog) Check(state *types.JogState, now time.Time, expected time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	event := types.StaleEvent{Fingerprint: state.Meta.Fingerprint, ExpectedMs: logging.Millis(expected)}
+	var gap time.Duration
+	if !w.polledAt.IsZero() {
+		gap = now.Sub(w.polledAt)
+		event.GapMs = logging.Millis(gap)
+	}
+	w.polledAt, w.expected = now, expected
+
+	changed := state.Meta.Fingerprint != w.fingerprint
+	if changed {
+		w.fingerprint = state.Meta.Fingerprint
+		w.pendingAt = time.Time{} // 새 데이터
+	}
+	if !w.pendingAt.IsZero() && now.Sub(w.jogAt) > w.jogWindow {
+		w.pendingAt = time.Time{} // JOG가 끝났으면 같은 응답이 정상
+	}
+
+	reason := ""
+	switch {
+	case !w.pendingAt.IsZero() && now.Sub(w.pendingAt) >= w.frozen:
+		reason = StaleFrozen
+		event.UnchangedMs = logging.Millis(now.Sub(w.pendingAt))
+		event.Message = fmt.Sprintf("JOG 명령 뒤 %v 동안 컨트롤러 응답이 바뀌지 않았습니다", now.Sub(w.pendingAt).Round(time.Millisecond))
+	case gap > expected+w.latePoll:
+		reason = StaleLatePoll
+		event.Message = fmt.Sprintf("상태 조회가 늦었습니다: 간격 %v (폴링 주기 %v)", gap.Round(time.Millisecond), expected)
+	}
+	state.Meta.Stale, state.Meta.StaleReason = reason != "", reason
+
+	if reason == w.reason {
+		return
+	}
+	prev := w.reason
+	w.reason = reason
+	event.Stale, event.Reason = reason != "", reason
+	switch {
+	case event.Stale:
+		logInfo("⚠️ 상태 데이터 stale (%s): %s", reason, event.Message)
+	case prev == StaleFrozen && !changed:
+		event.Message = "JOG 명령이 끝나 frozen 해제 (응답은 그대로)"
+	case prev == StaleFrozen:
+		event.Message = "새 상태 데이터 수신"
+	default:
+		event.Message = "상태 조회가 다시 제때 들어옴"
+	}
+	if !event.Stale {
+		logInfo("✅ 상태 데이터 정상 복귀: %s", event.Message)
+	}
+	if w.bus != nil {
+		w.bus.Publish(EventStale, event)
+	}
+}
+
+// Stale 지금 상태를 믿을 수 없는지와 이유 (마지막 판정, 또는 다음 조회가 지금 늦고 있음)
+func (w *StaleWatchdog) Stale(now time.Time) (bool, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.reason != "" {
+		return true, w.reason
+	}
+	if !w.polledAt.IsZero() && now.Sub(w.polledAt) > w.expected+w.latePoll {
+		return true, StaleLatePoll
+	}
+	return false, ""
+}
+
+// Blocking stale인 동안 JOG 명령을 거부하는지
+func (w *StaleWatchdog) Blocking() bool {
+	return w.block
+}
+
+// ============================================================================
+// JOG 차단 (Stale Guard)
+// ============================================================================
+
+// StaleGuardController 성공한 JOG 명령을 감시기에 알리고, block_jog면 stale인 동안 JOG를 거부하는 Controller 데코레이터
+type StaleGuardController struct {
+	inner    Controller
+	watchdog *StaleWatchdog
+}
+
+// NewStaleGuardController StaleGuardController 생성
+func NewStaleGuardController(inner Controller, watchdog *StaleWatchdog) *StaleGuardController {
+	return &StaleGuardController{inner: inner, watchdog: watchdog}
+}
+
+// SendJogCommand stale이면 거부 (block_jog), 성공하면 감시기에 기록 (중단 명령은 그대로 전달)
+func (c *StaleGuardController) SendJogCommand(cmd types.JogCommand) (*types.JogResponse, error) {
+	if cmd.Dir == "stop" {
+		return c.inner.SendJogCommand(cmd)
+	}
+	if c.watchdog.Blocking() {
+		if stale, reason := c.watchdog.Stale(time.Now()); stale {
+			return rejectedResponse(types.ErrCodeStaleData, "상태 데이터를 믿을 수 없어 JOG를 거부합니다 ("+reason+") - 새 데이터가 들어오면 다시 시도하세요")
+		}
+	}
+
+	resp, err := c.inner.SendJogCommand(cmd)
+	if err == nil && resp != nil && resp.Success {
+		c.watchdog.NoteJog(time.Now())
+	}
+	return resp, err
+}
+
+// SetJogMode 그대로 전달
+func (c *StaleGuardController) SetJogMode(mode string) (*types.JogResponse, error) {
+	return c.inner.SetJogMode(mode)
+}
+
+// SetAxis 그대로 전달
+func (c *StaleGuardController) SetAxis(axis int, robot int) (*types.JogResponse, error) {
+	return c.inner.SetAxis(axis, robot)
+}
+
+// DisableJog 그대로 전달
+func (c *StaleGuardController) DisableJog() (*types.JogResponse, error) {
+	return c.inner.DisableJog()
+}
+
+// GetRobotData 그대로 전달
+func (c *StaleGuardController) GetRobotData() (*types.JogState, error) {
+	return c.inner.GetRobotData()
+}
+
+// ConnectionStatus 그대로 전달
+func (c *StaleGuardController) ConnectionStatus() types.ConnectionStatus {
+	return c.inner.ConnectionStatus()
+}
@@ -0,0 +1,466 @@
+// ============================================================================
+// internal/simulator/simulator.go - 가상 로봇 컨트롤러 시뮬레이터
+// ============================================================================
+// 실제 로봇(192.168.0.1) 없이 개발할 수 있도록 컨트롤러의 웹 인터페이스를
+// 흉내 내는 HTTP 서버입니다. HTTPController는 주소만 바꾸면 실제 장비와
+// 동일한 프로토콜로 시뮬레이터와 통신합니다.
+//
+// 주요 기능:
+// - /wrtpdb 폼 전송 처리 (PID 215/620/621/622/623/624 쓰기)
+// - jogrefresh.asp 파이프(|) 구분 상태 데이터 제공
+// - 조인트/카르테시안 JOG 움직임의 시간 적분 (SCARA 기구학)
+// - JOG 트리거가 끊기면 자동으로 멈추는 데드맨 동작
+// ============================================================================
+
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nir414/go-virtual-pendant/internal/robot"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// 시뮬레이터 기본값
+const (
+	DEFAULT_AXIS_COUNT = 6
+	DEFAULT_JOG_SPEED  = 20.0                   // 스텝 1.0당 초당 이동량 (deg/s, mm/s)
+	DEFAULT_JOG_HOLD   = 150 * time.Millisecond // 마지막 JOG 명령 후 움직임 유지 시간
+	DEFAULT_LINK1      = 225.0                  // SCARA 1번 링크 길이 (mm)
+	DEFAULT_LINK2      = 175.0                  // SCARA 2번 링크 길이 (mm)
+)
+
+// JOG 모드 번호 (PID 621 값)
+const (
+	modeComputer = 0
+	modeJoint    = 1
+	modeWorld    = 2
+	modeTool     = 3
+	modeFree     = 4
+)
+
+// 상태 데이터 필드 수 (jogrefresh.asp)
+const (
+	cartesianCount = 6
+	jointCount     = 12
+	toolCount      = 6
+)
+
+// ============================================================================
+// 시뮬레이터 타입 (Simulator Types)
+// ============================================================================
+
+// Options 시뮬레이터 생성 옵션
+type Options struct {
+	AxisCount int              // 보고할 축 개수
+	JogSpeed  float64          // 스텝 1.0당 초당 이동량
+	JogHold   time.Duration    // JOG 트리거 유지 시간 (데드맨)
+	Link1     float64          // SCARA 1번 링크 길이 (mm)
+	Link2     float64          // SCARA 2번 링크 길이 (mm)
+	Now       func() time.Time // 시간 소스 (nil이면 time.Now)
+}
+
+// jogMotion 현재 진행 중인 JOG 움직임
+type jogMotion struct {
+	pid   string  // robot.JointModePID 또는 robot.CartesianModePID
+	axis  int     // 1부터 시작하는 축 번호
+	step  float64 // 부호 포함 스텝
+	until time.Time
+}
+
+// Simulator 가상 컨트롤러 모델
+type Simulator struct {
+	mu   sync.Mutex
+	opts Options
+
+	cartesian [cartesianCount]float64
+	joint     [jointCount]float64
+	tool      [toolCount]float64
+
+	jogEnable     bool
+	jogMode       int
+	selectedAxis  int
+	selectedRobot int
+	powerState    int
+	errorDesc     string
+
+	motion     *jogMotion
+	lastUpdate time.Time
+}
+
+// New 시뮬레이터 생성 (초기 자세는 SCARA 기준 J2=90°)
+func New(opts Options) *Simulator {
+	if opts.AxisCount <= 0 || opts.AxisCount > jointCount {
+		opts.AxisCount = DEFAULT_AXIS_COUNT
+	}
+	if opts.JogSpeed <= 0 {
+		opts.JogSpeed = DEFAULT_JOG_SPEED
+	}
+	if opts.JogHold <= 0 {
+		opts.JogHold = DEFAULT_JOG_HOLD
+	}
+	if opts.Link1 <= 0 {
+		opts.Link1 = DEFAULT_LINK1
+	}
+	if opts.Link2 <= 0 {
+		opts.Link2 = DEFAULT_LINK2
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	s := &Simulator{
+		opts:          opts,
+		jogMode:       modeComputer,
+		selectedAxis:  1,
+		selectedRobot: 1,
+		powerState:    1,
+		lastUpdate:    opts.Now(),
+	}
+	s.joint[1] = 90
+	s.joint[2] = 100
+	s.updateCartesianFromJoints()
+	return s
+}
+
+// Handler 컨트롤러 웹 인터페이스 경로가 등록된 http.Handler 반환
+func (s *Simulator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(robot.ROBOT_COMMAND_PATH, s.handleWrite)
+	mux.HandleFunc(robot.ROBOT_DATA_PATH, s.handleRefresh)
+	mux.HandleFunc(robot.ROBOT_REDIRECT, s.handleRedirectPage)
+	return mux
+}
+
+// ============================================================================
+// HTTP 핸들러 (HTTP Handlers)
+// ============================================================================
+
+// handleWrite /wrtpdb 폼 전송 처리
+func (s *Simulator) handleWrite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeErr := s.applyForm(r.PostForm)
+
+	redirect := r.PostForm.Get("Redirect")
+	if redirect == "" {
+		redirect = robot.ROBOT_REDIRECT
+	}
+	if writeErr != nil {
+		redirect += "?ErrMsg=" + url.QueryEscape(writeErr.Error())
+	}
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// handleRefresh jogrefresh.asp 상태 데이터 제공
+func (s *Simulator) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, s.Payload())
+}
+
+// handleRedirectPage /wrtpdb 처리 후 리다이렉트되는 결과 페이지
+func (s *Simulator) handleRedirectPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if msg := r.URL.Query().Get("ErrMsg"); msg != "" {
+		fmt.Fprintf(w, "<html><body><span id=\"ErrMsg\">Error: %s</span></body></html>", htmlEscape(msg))
+		return
+	}
+	fmt.Fprint(w, "<html><body>OK</body></html>")
+}
+
+// ============================================================================
+// 상태 모델 (State Model)
+// ============================================================================
+
+// pidWrite 폼에서 추출한 단일 PID 쓰기
+type pidWrite struct {
+	pid   string
+	index int
+	value float64
+}
+
+// applyForm /wrtpdb 폼의 PID 쓰기를 순서대로 모델에 적용
+func (s *Simulator) applyForm(form url.Values) error {
+	writes, err := parseWrites(form)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.opts.Now()
+	s.advance(now)
+
+	for i, wr := range writes {
+		// buildJogCommand는 이동 명령 뒤에 PID2=624,1 값 1을 JOG 트리거로 붙임
+		if i > 0 && isJogTrigger(wr) && isJogMotion(writes[i-1]) {
+			continue
+		}
+		if err := s.applyWrite(wr, now); err != nil {
+			s.errorDesc = err.Error()
+			return err
+		}
+	}
+	return nil
+}
+
+// applyWrite PID 쓰기 하나를 모델에 적용 (호출자가 잠금 보유)
+func (s *Simulator) applyWrite(wr pidWrite, now time.Time) error {
+	switch wr.pid {
+	case "0":
+		// 조깅 중단 (PID1=0,0,0,0)
+		s.motion = nil
+	case robot.PID_JOG_ENABLE:
+		s.jogEnable = wr.value != 0
+		if !s.jogEnable {
+			s.motion = nil
+		}
+	case robot.PID_ROBOT_SELECT:
+		s.selectedRobot = int(wr.value)
+	case robot.PID_JOG_MODE:
+		mode := int(wr.value)
+		if mode < modeComputer || mode > modeFree {
+			return fmt.Errorf("Invalid jog mode %d", mode)
+		}
+		if mode != s.jogMode {
+			s.motion = nil
+		}
+		s.jogMode = mode
+	case robot.PID_AXIS_SELECT:
+		s.selectedAxis = int(wr.value)
+	case robot.JointModePID, robot.CartesianModePID:
+		return s.startJog(wr, now)
+	default:
+		return fmt.Errorf("Unknown PID %s", wr.pid)
+	}
+	s.errorDesc = ""
+	return nil
+}
+
+// startJog JOG 이동 명령 적용 (데드맨 시간 갱신)
+func (s *Simulator) startJog(wr pidWrite, now time.Time) error {
+	if !s.jogEnable || s.powerState == 0 {
+		return fmt.Errorf("Jog not enabled")
+	}
+
+	switch wr.pid {
+	case robot.JointModePID:
+		if s.jogMode != modeJoint {
+			return fmt.Errorf("Joint jog requires Joint mode")
+		}
+		if wr.index < 1 || wr.index > s.opts.AxisCount {
+			return fmt.Errorf("Invalid joint %d", wr.index)
+		}
+	case robot.CartesianModePID:
+		if s.jogMode != modeWorld && s.jogMode != modeTool {
+			return fmt.Errorf("Cartesian jog requires World or Tool mode")
+		}
+		if wr.index < 1 || wr.index > cartesianCount {
+			return fmt.Errorf("Invalid cartesian axis %d", wr.index)
+		}
+	}
+
+	s.motion = &jogMotion{
+		pid:   wr.pid,
+		axis:  wr.index,
+		step:  wr.value,
+		until: now.Add(s.opts.JogHold),
+	}
+	s.errorDesc = ""
+	return nil
+}
+
+// advance 마지막 갱신 이후 경과 시간만큼 JOG 움직임을 적분 (호출자가 잠금 보유)
+func (s *Simulator) advance(now time.Time) {
+	last := s.lastUpdate
+	s.lastUpdate = now
+	if s.motion == nil {
+		return
+	}
+
+	end := now
+	if s.motion.until.Before(end) {
+		end = s.motion.until
+	}
+	dt := end.Sub(last).Seconds()
+	if dt > 0 {
+		delta := s.motion.step * s.opts.JogSpeed * dt
+		if s.motion.pid == robot.JointModePID {
+			s.joint[s.motion.axis-1] += delta
+			s.updateCartesianFromJoints()
+		} else {
+			s.moveCartesian(s.motion.axis-1, delta)
+		}
+	}
+
+	// 데드맨: 트리거가 갱신되지 않으면 정지
+	if !now.Before(s.motion.until) {
+		s.motion = nil
+	}
+}
+
+// moveCartesian 카르테시안 축 이동 후 역기구학으로 조인트 갱신
+func (s *Simulator) moveCartesian(index int, delta float64) {
+	target := s.cartesian
+	target[index] += delta
+
+	// Rx, Ry는 SCARA 기구학과 무관하므로 값만 적분
+	if index == 3 || index == 4 {
+		s.cartesian = target
+		return
+	}
+
+	j1, j2, ok := scaraInverse(target[0], target[1], s.opts.Link1, s.opts.Link2, s.joint[1] >= 0)
+	if !ok {
+		s.motion = nil
+		s.errorDesc = "Position out of reach"
+		return
+	}
+	s.joint[0] = j1
+	s.joint[1] = j2
+	s.joint[2] = target[2]
+	s.joint[3] = target[5] - j1 - j2
+	s.cartesian = target
+}
+
+// updateCartesianFromJoints 순기구학으로 카르테시안 좌표 갱신
+func (s *Simulator) updateCartesianFromJoints() {
+	x, y := scaraForward(s.joint[0], s.joint[1], s.opts.Link1, s.opts.Link2)
+	s.cartesian[0] = x
+	s.cartesian[1] = y
+	s.cartesian[2] = s.joint[2]
+	s.cartesian[5] = s.joint[0] + s.joint[1] + s.joint[3]
+}
+
+// Payload 현재 상태를 jogrefresh.asp 형식 문자열로 반환
+func (s *Simulator) Payload() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance(s.opts.Now())
+
+	fields := make([]string, 0, 25)
+	for _, v := range s.cartesian {
+		fields = append(fields, formatValue(v))
+	}
+	for _, v := range s.joint {
+		fields = append(fields, formatValue(v))
+	}
+	fields = append(fields,
+		strconv.Itoa(s.selectedAxis), // jData[18]
+		strconv.Itoa(s.opts.AxisCount),
+		boolValue(s.jogEnable && s.jogMode != modeComputer && s.powerState != 0),
+		strconv.Itoa(s.jogMode),
+		strconv.Itoa(s.powerState),
+		s.errorDesc,
+	)
+
+	tool := make([]string, 0, toolCount)
+	for _, v := range s.tool {
+		tool = append(tool, formatValue(v))
+	}
+	fields = append(fields, strings.Join(tool, ","))
+
+	return strings.Join(fields, "|")
+}
+
+// ============================================================================
+// 유틸리티 함수 (Utility Functions)
+// ============================================================================
+
+// parseWrites 폼에서 nPID 개수만큼 PID/PVal 쌍 추출
+func parseWrites(form url.Values) ([]pidWrite, error) {
+	n, err := strconv.Atoi(form.Get("nPID"))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("Invalid nPID")
+	}
+
+	writes := make([]pidWrite, 0, n)
+	for i := 1; i <= n; i++ {
+		pid := form.Get(fmt.Sprintf("PID%d", i))
+		if pid == "" {
+			// 중단 명령처럼 nPID보다 적은 PID가 전송될 수 있음
+			continue
+		}
+		parts := strings.Split(pid, ",")
+		wr := pidWrite{pid: strings.TrimSpace(parts[0])}
+		if len(parts) > 1 {
+			wr.index, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+		}
+		wr.value, err = strconv.ParseFloat(strings.TrimSpace(form.Get(fmt.Sprintf("PVal%d", i))), 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid PVal%d", i)
+		}
+		writes = append(writes, wr)
+	}
+	return writes, nil
+}
+
+// isJogTrigger PID2=624,1 값 1 형태의 JOG 트리거인지 확인
+func isJogTrigger(wr pidWrite) bool {
+	return wr.pid == robot.CartesianModePID && wr.index == 1 && wr.value == 1
+}
+
+// isJogMotion 623/624 이동 명령인지 확인
+func isJogMotion(wr pidWrite) bool {
+	return wr.pid == robot.JointModePID || wr.pid == robot.CartesianModePID
+}
+
+// scaraForward SCARA 순기구학 (각도 단위: 도)
+func scaraForward(j1, j2, l1, l2 float64) (float64, float64) {
+	a1 := j1 * math.Pi / 180
+	a12 := (j1 + j2) * math.Pi / 180
+	return l1*math.Cos(a1) + l2*math.Cos(a12), l1*math.Sin(a1) + l2*math.Sin(a12)
+}
+
+// scaraInverse SCARA 역기구학 (elbowPositive는 J2 부호 유지용)
+func scaraInverse(x, y, l1, l2 float64, elbowPositive bool) (float64, float64, bool) {
+	c2 := (x*x + y*y - l1*l1 - l2*l2) / (2 * l1 * l2)
+	if c2 < -1 || c2 > 1 {
+		return 0, 0, false
+	}
+	s2 := math.Sqrt(1 - c2*c2)
+	if !elbowPositive {
+		s2 = -s2
+	}
+	j2 := math.Atan2(s2, c2)
+	j1 := math.Atan2(y, x) - math.Atan2(l2*s2, l1+l2*c2)
+	return j1 * 180 / math.Pi, j2 * 180 / math.Pi, true
+}
+
+// formatValue 상태 데이터용 소수점 3자리 포맷
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// boolValue bool을 "1"/"0"으로 변환
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// htmlEscape 결과 페이지 출력용 최소 HTML 이스케이프
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -14,6 +14,12 @@
 
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // ============================================================================
 // 멀티 스택 호환 명령 타입 (Multi-Stack Command Types)
 // ============================================================================
@@ -24,6 +30,14 @@ type JogCommand struct {
 	Dir  string  `json:"dir"`  // "positive", "negative"
 	Step float64 `json:"step"` // 이동 거리/각도
 	Mode string  `json:"mode"` // "joint", "cartesian"
+
+	// 순서 보장 (선택): 같은 ClientSession에서 단조 증가하는 Seq
+	// 마지막 중단 명령의 Seq 이하인 JOG 명령은 전송하지 않고 폐기됩니다.
+	// ClientSession이 없으면 Seq는 무시됩니다 (익명 클라이언트끼리 순번이 섞이지 않도록).
+	Seq           uint64 `json:"seq,omitempty"`
+	ClientSession string `json:"client_session,omitempty"` // 브라우저 탭 등 클라이언트 세션 식별자
+
+	Meta RequestMeta `json:"meta,omitempty"` // 요청 메타데이터 (TraceID는 명령 로그에 기록)
 }
 
 // JogResponse JOG 명령 응답 구조체 (표준 웹 API 응답 형식)
@@ -35,6 +49,23 @@ type JogResponse struct {
 	ErrorCode string `json:"error_code,omitempty"` // 에러 코드 (디버깅용)
 }
 
+// JogResponse.ErrorCode 값 (HTTP 상태 코드 매핑에 사용)
+const (
+	ErrCodeInvalidCommand     = "INVALID_COMMAND"                // 명령 생성 실패 (잘못된 축/모드)
+	ErrCodeUnreachable        = "CONTROLLER_UNREACHABLE"         // 컨트롤러 연결 실패
+	ErrCodeTimeout            = "CONTROLLER_TIMEOUT"             // 컨트롤러 응답 시간 초과
+	ErrCodeHTTPStatus         = "CONTROLLER_HTTP_ERROR"          // 컨트롤러가 4xx/5xx 응답
+	ErrCodeAuthRequired       = "CONTROLLER_AUTH_REQUIRED"       // 로그인 페이지로 리다이렉트
+	ErrCodeUnexpectedRedirect = "CONTROLLER_UNEXPECTED_REDIRECT" // dbfunctions.asp 외의 경로로 리다이렉트
+	ErrCodeRejected           = "CONTROLLER_REJECTED"            // 컨트롤러가 오류 메시지로 거부
+	ErrCodeSessionNotFound    = "JOG_SESSION_NOT_FOUND"          // 종료되었거나 없는 연속 JOG 세션
+	ErrCodeStaleCommand       = "STALE_COMMAND"                  // 중단 명령보다 먼저 보낸 JOG 명령 (폐기)
+	ErrCodeQueueFull          = "COMMAND_QUEUE_FULL"             // 컨트롤러 명령 큐 포화
+	ErrCodeSoftLimit          = "SOFT_LIMIT_EXCEEDED"            // 소프트 리밋(조인트 범위/작업 영역)을 벗어나는 JOG
+	ErrCodeStateUnavailable   = "SOFT_LIMIT_STATE_UNAVAILABLE"   // 현재 위치를 몰라 소프트 리밋 확인 불가
+	ErrCodeStaleData          = "STALE_STATE_DATA"               // 상태 데이터가 멈춰 있거나 늦어 JOG 차단 (watchdog.block_jog)
+)
+
 // ============================================================================
 // 크로스 플랫폼 상태 타입 (Cross-Platform State Types)
 // ============================================================================
@@ -46,6 +77,21 @@ type JogState struct {
 	ToolData  []float64 `json:"tool"`      // 툴 데이터
 	Status    JogStatus `json:"status"`    // 상태 정보
 	Meta      StateMeta `json:"meta"`      // 메타데이터 (디버깅/로깅용)
+
+	Kinematics *Kinematics `json:"kinematics,omitempty"` // 속도/가속도 추정 (상태 브로커가 계산)
+}
+
+// Kinematics 연속된 상태 조회로 추정한 축별 속도와 가속도
+// 단위는 위치 단위/초 (조인트 °/s 또는 mm/s, 카르테시안 mm/s, 회전 °/s)와 그 /s입니다.
+// 배열 순서는 로봇 모델 축 순서와 같습니다.
+type Kinematics struct {
+	JointVelocity         []float64 `json:"joint_velocity"`
+	JointAcceleration     []float64 `json:"joint_acceleration"`
+	CartesianVelocity     []float64 `json:"cartesian_velocity"`
+	CartesianAcceleration []float64 `json:"cartesian_acceleration"`
+	IntervalMs            float64   `json:"interval_ms"` // 직전 샘플과의 실제 시간 간격
+	Valid                 bool      `json:"valid"`       // false면 비교할 샘플이 없어 0으로 채움 (첫 샘플, 조회 공백 후)
+	Moving                bool      `json:"moving"`      // 속도가 still_velocity를 넘는 축이 있음
 }
 
 // JogStatus 로봇 상태 정보 구조체 (JavaScript 친화적)
@@ -58,16 +104,178 @@ type JogStatus struct {
 	SelectedAxisText string `json:"selected_axis_text"` // 축명 (J1, X, etc.)
 	PowerState       int    `json:"power_state"`
 	ErrorDesc        string `json:"error_desc"`
-	IsConnected      bool   `json:"is_connected"` // 연결 상태 (웹 UI용)
+	IsConnected      bool   `json:"is_connected"`     // 연결 상태 (웹 UI용)
+	ConnectionState  string `json:"connection_state"` // "connecting", "connected", "degraded", "disconnected"
+}
+
+// ConnectionStatus 컨트롤러 연결 상태 상세 (/api/connection 응답)
+type ConnectionStatus struct {
+	State               string `json:"state"`     // "connecting", "connected", "degraded", "disconnected"
+	Connected           bool   `json:"connected"` // connected 또는 degraded
+	Since               string `json:"since"`     // 현재 상태 진입 시각 (ISO 8601)
+	LastSuccess         string `json:"last_success,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
+	LastErrorMessage    string `json:"last_error_message,omitempty"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	RetryInMs           int64  `json:"retry_in_ms,omitempty"` // 재연결 대기 남은 시간
 }
 
 // StateMeta 상태 메타데이터 (디버깅 및 멀티 스택 지원)
 type StateMeta struct {
-	Timestamp   string `json:"timestamp"`   // ISO 8601 형식
+	Timestamp   string `json:"timestamp"`   // ISO 8601 형식 (컨트롤러에서 읽은 시각)
 	Source      string `json:"source"`      // "go-server", "js-client", "chrome-extension"
+	Seq         uint64 `json:"seq"`         // 상태 브로커 조회 순번 (같은 값이면 같은 상태)
+	AgeMs       int64  `json:"age_ms"`      // 응답 시점의 상태 나이 (캐시에서 응답한 경우)
 	Version     string `json:"version"`     // API 버전
 	Environment string `json:"environment"` // "development", "production", "test"
 	DebugMode   bool   `json:"debug_mode"`  // 디버그 모드 여부
+
+	// jogrefresh.asp 응답 해석 결과 (진단용)
+	Layout        string              `json:"layout,omitempty"`         // 사용한 응답 레이아웃 이름
+	ParseErrors   []PayloadFieldError `json:"parse_errors,omitempty"`   // 값을 읽지 못한 필드 (값은 0으로 둠)
+	UnknownFields []PayloadRawField   `json:"unknown_fields,omitempty"` // 레이아웃에 없는 비어 있지 않은 필드
+
+	// 상태 데이터 감시 (멈춘 응답, 늦은 조회)
+	Fingerprint string `json:"fingerprint,omitempty"`  // jogrefresh.asp 원본 응답 해시 (같으면 같은 응답)
+	Stale       bool   `json:"stale,omitempty"`        // 이 상태를 믿을 수 없음 (StaleReason 참고)
+	StaleReason string `json:"stale_reason,omitempty"` // "frozen" (JOG 중 응답이 바뀌지 않음), "late_poll" (조회가 늦음)
+}
+
+// PayloadFieldError 응답 필드 하나의 파싱 오류
+type PayloadFieldError struct {
+	Index int    `json:"index"` // 파이프 구분 필드 위치 (0부터)
+	Field string `json:"field"` // 대상 (예: "joint[2]", "jog_mode")
+	Raw   string `json:"raw"`   // 받은 값
+	Error string `json:"error"`
+}
+
+// PayloadRawField 레이아웃에 없는 응답 필드 (원본 그대로)
+type PayloadRawField struct {
+	Index int    `json:"index"`
+	Value string `json:"value"`
+}
+
+// RobotEvent 로봇 이벤트 (/api/events SSE 스트림)
+type RobotEvent struct {
+	ID   uint64      `json:"id"`   // 단조 증가 (SSE id, Last-Event-ID 재전송 기준)
+	Type string      `json:"type"` // "state", "mode_changed", "error", "connection", "command"
+	Time string      `json:"time"` // 이벤트 발생 시각 (ISO 8601)
+	Data interface{} `json:"data"` // 종류별 본문 (아래 *Event 타입, state는 JogState)
+}
+
+// StateChangeEvent 연속된 JogState 비교로 감지한 이벤트 (robot.EventDetector)
+type StateChangeEvent struct {
+	Type  string      `json:"type"`            // robot.Detect* 상수 ("motion_started", "mode_changed", ...)
+	Seq   uint64      `json:"seq"`             // 이벤트를 만든 상태의 조회 순번
+	Time  string      `json:"time"`            // 상태를 읽은 시각
+	Axis  string      `json:"axis,omitempty"`  // 축 이벤트: 모델 축 이름 (joint1, x, ...)
+	Group string      `json:"group,omitempty"` // 축 이벤트: "joint", "cartesian"
+	From  interface{} `json:"from"`            // 이전 값 (motion_stopped는 움직이기 시작한 위치)
+	To    interface{} `json:"to"`              // 현재 값
+	State *JogState   `json:"-"`               // 이벤트를 만든 상태 (읽기 전용으로 공유)
+}
+
+// HistoryResponse 위치 이력 조회 결과 (/api/history)
+// downsample을 지정하지 않으면 samples, 지정하면 buckets만 채워집니다.
+type HistoryResponse struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Axes         []string        `json:"axes"` // values/min/max/avg 배열의 축 순서
+	DownsampleMs int64           `json:"downsample_ms,omitempty"`
+	Samples      []HistorySample `json:"samples,omitempty"`
+	Buckets      []HistoryBucket `json:"buckets,omitempty"`
+}
+
+// HistorySample 상태 샘플 하나
+type HistorySample struct {
+	Time   string    `json:"time"`
+	Seq    uint64    `json:"seq"`
+	Values []float64 `json:"values"`
+}
+
+// HistoryBucket 시간 구간 하나의 축별 최소/최대/평균
+type HistoryBucket struct {
+	Time  string    `json:"time"`  // 구간 시작 시각
+	Count int       `json:"count"` // 구간에 포함된 샘플 수
+	Min   []float64 `json:"min"`
+	Max   []float64 `json:"max"`
+	Avg   []float64 `json:"avg"`
+}
+
+// RecordingStartRequest 궤적 기록 시작 요청
+type RecordingStartRequest struct {
+	Name   string      `json:"name"`             // 파일 이름 (영문, 숫자, '-', '_', '.' - 확장자 제외)
+	Format string      `json:"format,omitempty"` // "csv"(기본) 또는 "jsonl"
+	Meta   RequestMeta `json:"meta,omitempty"`
+}
+
+// RecordingRequest 궤적 기록 중단 요청
+type RecordingRequest struct {
+	Name string `json:"name"`
+}
+
+// RecordingInfo 궤적 기록 정보
+type RecordingInfo struct {
+	Name      string `json:"name"`
+	Format    string `json:"format"` // "csv", "jsonl"
+	File      string `json:"file"`   // 파일 이름 (디렉터리 제외)
+	Active    bool   `json:"active"`
+	StartedBy string `json:"started_by,omitempty"`
+	StartedAt string `json:"started_at,omitempty"`
+	StoppedAt string `json:"stopped_at,omitempty"`
+	States    int    `json:"states"`   // 기록한 상태 샘플 수 (진행 중이거나 이번 실행에서 기록한 경우)
+	Commands  int    `json:"commands"` // 기록한 명령 수
+	SizeBytes int64  `json:"size_bytes"`
+	Error     string `json:"error,omitempty"` // 쓰기 실패로 중단된 경우
+}
+
+// RecordingResponse 궤적 기록 시작/중단 응답
+type RecordingResponse struct {
+	Success   bool           `json:"success"`
+	Message   string         `json:"message"`
+	Recording *RecordingInfo `json:"recording,omitempty"`
+}
+
+// ModeChangedEvent JOG 모드 변경 (mode_changed)
+type ModeChangedEvent struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	FromText string `json:"from_text"`
+	ToText   string `json:"to_text"`
+}
+
+// ErrorEvent 상태 조회 실패 또는 컨트롤러 오류 메시지 (error)
+type ErrorEvent struct {
+	Source  string `json:"source"`            // "poll" (상태 조회 실패), "controller" (error_desc)
+	Message string `json:"message,omitempty"` // 빈 값이면 오류 해제
+}
+
+// StaleEvent 상태 데이터 감시 결과 변경 (stale)
+type StaleEvent struct {
+	Stale       bool    `json:"stale"`            // false면 새 데이터가 들어와 해제
+	Reason      string  `json:"reason,omitempty"` // "frozen", "late_poll"
+	Message     string  `json:"message"`
+	Fingerprint string  `json:"fingerprint,omitempty"`  // 마지막 응답 해시
+	UnchangedMs float64 `json:"unchanged_ms,omitempty"` // JOG 명령 뒤 응답이 바뀌지 않은 시간
+	GapMs       float64 `json:"gap_ms,omitempty"`       // 직전 성공 조회와의 간격
+	ExpectedMs  float64 `json:"expected_ms,omitempty"`  // 그때의 폴링 주기
+}
+
+// ConnectionEvent 컨트롤러 연결 상태 변경 (connection)
+type ConnectionEvent struct {
+	From   string           `json:"from"`
+	To     string           `json:"to"`
+	Status ConnectionStatus `json:"status"`
+}
+
+// CommandEvent 클라이언트가 보낸 명령과 결과 (command)
+// 연속 JOG 세션의 반복 전송은 포함하지 않고 세션 시작/중단만 기록합니다.
+type CommandEvent struct {
+	Action    string      `json:"action"`            // "jog", "set_mode", "set_axis", "disable_jog", "jog_session_start", "jog_session_stop", "all_stop"
+	Request   interface{} `json:"request,omitempty"` // 요청 내용 (JogCommand, 모드, 축 번호 등)
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	ErrorCode string      `json:"error_code,omitempty"`
 }
 
 // ============================================================================
@@ -84,6 +292,46 @@ const (
 	LogLevelVerbose
 )
 
+// String 로깅 레벨 이름 반환 ("INFO", "DEBUG", "VERBOSE")
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelVerbose:
+		return "VERBOSE"
+	default:
+		return "INFO"
+	}
+}
+
+// ParseLogLevel 이름으로 로깅 레벨 조회 (대소문자 무시)
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "", "INFO":
+		return LogLevelInfo, nil
+	case "DEBUG":
+		return LogLevelDebug, nil
+	case "VERBOSE":
+		return LogLevelVerbose, nil
+	}
+	return LogLevelInfo, fmt.Errorf("알 수 없는 로그 레벨: %s", name)
+}
+
+// MarshalText JSON에서 레벨 이름으로 직렬화
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText JSON의 레벨 이름을 파싱
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
 // Environment 환경 타입 (멀티 스택 지원)
 type Environment string
 
@@ -113,11 +361,13 @@ type AxisConfig struct {
 	Axis int
 }
 
-// AxisInfo 축 정보 구조체 (이름과 표시명 포함)
+// AxisInfo 축 정보 구조체 (이름과 표시명 포함, 로봇 모델 파일의 축 항목)
 type AxisInfo struct {
-	Config      AxisConfig
-	DisplayName string
-	Aliases     []string // 별칭들 (j1, joint1 등)
+	Config      AxisConfig `json:"-"`
+	DisplayName string     `json:"name"`            // 표시명 (J1, X 등)
+	Aliases     []string   `json:"aliases"`         // 별칭들 (j1, joint1 등) - API의 axis 값
+	Unit        string     `json:"unit,omitempty"`  // 단위 ("deg", "mm")
+	Limit       *AxisLimit `json:"limit,omitempty"` // 모델 기본 소프트 리밋 (설정 파일 limits가 우선)
 }
 
 // ModeConfig JOG 모드 설정 구조체
@@ -126,11 +376,44 @@ type ModeConfig struct {
 	JogMode string
 }
 
-// ModeInfo JOG 모드 정보 구조체 (설정과 표시명 포함)
+// ModeInfo JOG 모드 정보 구조체 (설정과 표시명 포함, 로봇 모델 파일의 모드 항목)
 type ModeInfo struct {
-	Config      ModeConfig
-	DisplayName string
-	ModeNumber  int
+	Config      ModeConfig `json:"-"`
+	DisplayName string     `json:"name"`           // 표시명 (Joint, World 등) - 소문자가 API의 mode 값
+	ModeNumber  int        `json:"number"`         // PID 621 값
+	JogEnable   bool       `json:"jog_enable"`     // 모드 변경 시 PID 215 값 (JOG 활성화)
+	Axes        string     `json:"axes,omitempty"` // JOG 축 집합 ("joint", "cartesian", 없으면 축 선택 없음 - Computer, Free)
+}
+
+// RobotModel 로봇 모델 정의 (축, 모드, PID, 리밋 - 모델 파일 최상위 구조)
+type RobotModel struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	JointPID     string     `json:"joint_pid"`     // 조인트 JOG PID (예: "623")
+	CartesianPID string     `json:"cartesian_pid"` // 카르테시안 JOG PID (예: "624")
+	TriggerPID   string     `json:"trigger_pid"`   // JOG 트리거 PID (없으면 cartesian_pid)
+	Joints       []AxisInfo `json:"joints"`        // JogState.Joint 순서 (최대 12)
+	Cartesian    []AxisInfo `json:"cartesian"`     // JogState.Cartesian 순서 (최대 6)
+	Modes        []ModeInfo `json:"modes"`
+}
+
+// PayloadLayout jogrefresh.asp 응답 레이아웃 (펌웨어별 필드 위치 - 레이아웃 파일 최상위 구조)
+type PayloadLayout struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Separator   string         `json:"separator,omitempty"`  // 필드 구분자 (기본 "|")
+	MinFields   int            `json:"min_fields,omitempty"` // 이보다 적으면 조회 실패 (0이면 마지막 필드 위치 + 1)
+	Fields      []PayloadField `json:"fields"`
+}
+
+// PayloadField 응답 필드와 JogState 항목의 대응
+type PayloadField struct {
+	Target   string `json:"target"`             // "cartesian", "joint", "tool", "axis_count", "allow_jog", "jog_mode", "power_state", "error_desc", "selected_axis", "ignore"
+	Index    int    `json:"index"`              // 필드 위치 (0부터)
+	Count    int    `json:"count,omitempty"`    // 배열 대상: 연속 필드 수 (기본 1)
+	Slot     int    `json:"slot,omitempty"`     // 배열 대상: 첫 값이 들어갈 배열 위치
+	Split    string `json:"split,omitempty"`    // 배열 대상: 필드 하나를 이 구분자로 나눠 여러 값으로 읽음 (예: 툴 ",")
+	Required bool   `json:"required,omitempty"` // 값을 읽지 못하면 조회 전체를 실패로 처리
 }
 
 // ============================================================================
@@ -150,6 +433,69 @@ type SetAxisRequest struct {
 	Meta  RequestMeta `json:"meta,omitempty"` // 요청 메타데이터
 }
 
+// JogSessionStartRequest 연속 JOG 세션 시작 요청 (JogCommand 필드, 메타데이터는 JogCommand.Meta)
+type JogSessionStartRequest struct {
+	JogCommand
+}
+
+// JogSessionRequest 연속 JOG 세션 하트비트/중단 요청
+type JogSessionRequest struct {
+	SessionID string      `json:"session_id"`
+	Meta      RequestMeta `json:"meta,omitempty"` // 요청 메타데이터
+}
+
+// JogSessionInfo 연속 JOG 세션 상태
+type JogSessionInfo struct {
+	SessionID          string     `json:"session_id"`
+	Owner              string     `json:"owner,omitempty"` // 세션을 시작한 클라이언트
+	Command            JogCommand `json:"command"`
+	Active             bool       `json:"active"`
+	StartedAt          string     `json:"started_at,omitempty"`
+	LastHeartbeat      string     `json:"last_heartbeat,omitempty"`
+	EndedAt            string     `json:"ended_at,omitempty"`
+	EndReason          string     `json:"end_reason,omitempty"` // "stopped", "heartbeat_timeout", "superseded", "command_failed", "stop_all", "connection_closed"
+	CommandsSent       int        `json:"commands_sent"`
+	HeartbeatTimeoutMs int64      `json:"heartbeat_timeout_ms"` // 이 시간 안에 하트비트를 보내야 함
+}
+
+// JogSessionResponse 연속 JOG 세션 API 응답
+type JogSessionResponse struct {
+	Success   bool            `json:"success"`
+	Message   string          `json:"message"`
+	Session   *JogSessionInfo `json:"session,omitempty"`
+	ErrorCode string          `json:"error_code,omitempty"`
+}
+
+// StopRequest 전체 정지 요청 (본문 없이 보내도 됨)
+type StopRequest struct {
+	Disable bool        `json:"disable"`          // 중단 후 JOG 비활성화 (PID 215 = 0, 모드 변경으로 재활성화)
+	Reason  string      `json:"reason,omitempty"` // 정지 사유 (감사 기록용)
+	Source  string      `json:"source,omitempty"` // 정지 경로 ("button", "keyboard", ...)
+	Meta    RequestMeta `json:"meta,omitempty"`   // 요청 메타데이터
+}
+
+// StopRecord 전체 정지 감사 기록
+type StopRecord struct {
+	ID                uint64  `json:"id"` // 서버 시작 후 전체 정지 순번
+	Time              string  `json:"time"`
+	TriggeredBy       string  `json:"triggered_by"`          // 클라이언트 ID (없으면 원격 주소)
+	RemoteAddr        string  `json:"remote_addr,omitempty"` // 요청 원격 주소
+	UserAgent         string  `json:"user_agent,omitempty"`
+	Source            string  `json:"source"` // StopRequest.Source (없으면 "api")
+	Reason            string  `json:"reason,omitempty"`
+	Success           bool    `json:"success"`              // 중단 명령이 컨트롤러에 전달되었는지
+	Message           string  `json:"message"`              // 중단 명령 결과
+	ErrorCode         string  `json:"error_code,omitempty"` // 중단 명령 실패 원인 (JOG 비활성화 실패는 DisableErrorCode)
+	QueuedStop        string  `json:"queued_stop"`          // 큐를 통한 재중단 결과: "sent", "failed", "pending"
+	DisableRequested  bool    `json:"disable_requested"`
+	JogDisabled       bool    `json:"jog_disabled"`
+	DisableMessage    string  `json:"disable_message,omitempty"`    // JOG 비활성화 결과
+	DisableErrorCode  string  `json:"disable_error_code,omitempty"` // JOG 비활성화 실패 원인
+	SessionsStopped   int     `json:"sessions_stopped"`             // 종료시킨 연속 JOG 세션 수
+	CommandsDiscarded int     `json:"commands_discarded"`           // 폐기한 대기 JOG 명령 수
+	DurationMs        float64 `json:"duration_ms"`
+}
+
 // RequestMeta 요청 메타데이터 (디버깅 및 추적용)
 type RequestMeta struct {
 	ClientID  string   `json:"client_id,omitempty"`  // 클라이언트 식별자
@@ -164,6 +510,124 @@ type RequestMeta struct {
 // 크로스 플랫폼 서버 설정 타입 (Cross-Platform Server Configuration)
 // ============================================================================
 
+// AppConfig 애플리케이션 전체 설정 (설정 파일 최상위 구조)
+type AppConfig struct {
+	Server     ServerConfig     `json:"server"`
+	Controller ControllerConfig `json:"controller"`
+	Jog        JogConfig        `json:"jog"`
+	Limits     SoftLimitConfig  `json:"limits"`
+	WebSocket  WebSocketConfig  `json:"websocket"`
+	Detector   DetectorConfig   `json:"detector"`
+	History    HistoryConfig    `json:"history"`
+	Recording  RecordingConfig  `json:"recording"`
+	Kinematics KinematicsConfig `json:"kinematics"`
+	Metrics    MetricsConfig    `json:"metrics"`
+	Health     HealthConfig     `json:"health"`
+	ClientLog  ClientLogConfig  `json:"client_log"`
+	Watchdog   WatchdogConfig   `json:"watchdog"`
+}
+
+// WatchdogConfig 상태 데이터 감시 설정 (멈춘 jogrefresh.asp 응답, 늦은 조회)
+type WatchdogConfig struct {
+	Enabled     bool `json:"enabled"`
+	FrozenMs    int  `json:"frozen_ms"`     // JOG 명령 뒤 응답이 이 시간 동안 같으면 frozen
+	JogWindowMs int  `json:"jog_window_ms"` // 마지막 JOG 명령 뒤 이 시간이 지나면 frozen 해제 (JOG 중이 아님)
+	LatePollMs  int  `json:"late_poll_ms"`  // 성공한 조회 간격이 폴링 주기 + 이 시간을 넘으면 late_poll
+	BlockJog    bool `json:"block_jog"`     // stale인 동안 JOG 명령 거부 (중단 명령은 항상 허용)
+}
+
+// ClientLogConfig 브라우저 클라이언트 로그 저장 설정 (/client-log, /api/client-logs)
+type ClientLogConfig struct {
+	Directory string `json:"directory"`   // 로그 파일 디렉터리 (없으면 생성)
+	MaxSizeMB int    `json:"max_size_mb"` // 파일 하나의 최대 크기 (넘으면 회전)
+	MaxFiles  int    `json:"max_files"`   // 보관할 파일 수 (현재 파일 포함)
+}
+
+// HealthConfig 준비 상태 판정 설정 (/readyz)
+type HealthConfig struct {
+	ReadyMaxAgeSec int `json:"ready_max_age_sec"` // 이 시간 안에 성공한 상태 조회가 있어야 준비됨 (0이면 자동)
+}
+
+// MetricsConfig Prometheus 메트릭 엔드포인트 설정
+type MetricsConfig struct {
+	Enable   bool   `json:"enable"`
+	Endpoint string `json:"endpoint"` // 예: "/metrics"
+}
+
+// KinematicsConfig 속도/가속도 추정 설정 (JogState.Kinematics)
+type KinematicsConfig struct {
+	FilterMs      int     `json:"filter_ms"`      // 저역 통과 필터 시간 상수 (0이면 필터 없음)
+	MaxGapMs      int     `json:"max_gap_ms"`     // 샘플 간격이 이보다 길면 추정을 다시 시작 (0이면 폴링 주기의 3배)
+	StillVelocity float64 `json:"still_velocity"` // 이 속도 이하면 정지로 판단 (Moving)
+}
+
+// RecordingConfig 궤적 기록 설정 (/api/recordings)
+type RecordingConfig struct {
+	Directory string `json:"directory"`  // 기록 파일 저장 디렉터리 (없으면 생성)
+	MaxActive int    `json:"max_active"` // 동시에 진행할 수 있는 기록 수
+}
+
+// HistoryConfig 위치 이력 버퍼 설정 (/api/history)
+// 두 조건 중 먼저 닿는 쪽에서 오래된 샘플을 버립니다.
+type HistoryConfig struct {
+	MaxSamples   int `json:"max_samples"`   // 보관할 최대 상태 샘플 수
+	RetentionSec int `json:"retention_sec"` // 보관 기간 (초)
+}
+
+// DetectorConfig 상태 이벤트 감지 임계값 (robot.EventDetector)
+// 연속된 두 상태의 차이가 임계값보다 커야 변화로 봅니다 (조인트 °/mm, 카르테시안 mm/°).
+type DetectorConfig struct {
+	MotionThreshold   float64 `json:"motion_threshold"`   // 축 움직임 판단 (조회 1회 사이 변화량)
+	StopSamples       int     `json:"stop_samples"`       // 변화가 없는 조회가 이만큼 이어지면 정지로 판단
+	PositionThreshold float64 `json:"position_threshold"` // position_changed 보고 (마지막 보고 위치 대비)
+	ToolThreshold     float64 `json:"tool_threshold"`     // tool_changed 판단
+}
+
+// SoftLimitConfig 서버 측 소프트 리밋 설정
+// 컨트롤러 자체 리밋보다 좁은 범위로 JOG를 제한합니다 (주변 설비 보호).
+type SoftLimitConfig struct {
+	Enabled       bool                 `json:"enabled"`          // 조인트 범위/작업 영역 검사 사용
+	MaxStep       float64              `json:"max_step"`         // 1회 JOG 최대 스텝 (° 또는 mm, 0이면 제한 없음 - 항상 적용)
+	MaxStateAgeMs int                  `json:"max_state_age_ms"` // 이보다 오래된 위치면 검사 전에 다시 조회 (밀리초)
+	Joints        map[string]AxisLimit `json:"joints"`           // 축 이름("joint1", "j2", ...) → 허용 범위 (°)
+	Cartesian     map[string]AxisLimit `json:"cartesian"`        // 축 이름("x", "y", "z", "rx", ...) → 허용 범위 (mm/°)
+}
+
+// AxisLimit 축 허용 범위
+type AxisLimit struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// JogConfig 서버 측 연속 JOG 세션 설정
+type JogConfig struct {
+	RepeatIntervalMs   int `json:"repeat_interval_ms"`   // 컨트롤러로 JOG 명령을 반복 전송하는 간격 (밀리초)
+	HeartbeatTimeoutMs int `json:"heartbeat_timeout_ms"` // 하트비트가 없으면 자동 중단하는 시간 (밀리초)
+}
+
+// ControllerConfig 로봇 컨트롤러 연결 설정
+type ControllerConfig struct {
+	Address        string `json:"address"`          // "192.168.0.1" 또는 "http://host:port"
+	TimeoutMs      int    `json:"timeout_ms"`       // HTTP 요청 타임아웃 (밀리초)
+	PollIntervalMs int    `json:"poll_interval_ms"` // 상태 모니터링 주기 (밀리초, 적응형 폴링의 기본 주기)
+	Simulate       bool   `json:"simulate"`         // 내장 가상 컨트롤러 사용
+	FollowRedirect bool   `json:"follow_redirect"`  // 명령 후 dbfunctions.asp 결과 페이지까지 확인
+	Model          string `json:"model"`            // 로봇 모델 파일 경로 (비어 있으면 내장 6축 모델)
+	PayloadLayout  string `json:"payload_layout"`   // jogrefresh.asp 응답 레이아웃 (내장 이름 또는 파일 경로, 비어 있으면 jogrefresh-v1)
+
+	// 적응형 폴링 (움직임/명령 중 빠르게, 보는 클라이언트가 없으면 느리게)
+	AdaptivePoll      bool `json:"adaptive_poll"`        // false면 poll_interval_ms 고정
+	PollMinIntervalMs int  `json:"poll_min_interval_ms"` // 움직임/명령 직후 주기 (밀리초)
+	PollMaxIntervalMs int  `json:"poll_max_interval_ms"` // 유휴 상태 최대 주기 (밀리초)
+
+	// 연결 상태 판정 (히스테리시스 및 재연결 대기)
+	FailuresToDegraded   int `json:"failures_to_degraded"`   // connected → degraded 연속 실패 횟수
+	FailuresToDisconnect int `json:"failures_to_disconnect"` // → disconnected 연속 실패 횟수
+	SuccessesToRecover   int `json:"successes_to_recover"`   // 재연결 시 connected 복귀 연속 성공 횟수
+	BackoffMinMs         int `json:"backoff_min_ms"`         // 재연결 최소 대기 (밀리초)
+	BackoffMaxMs         int `json:"backoff_max_ms"`         // 재연결 최대 대기 (밀리초)
+}
+
 // ServerConfig 서버 설정 (멀티 스택 환경 지원)
 type ServerConfig struct {
 	Port         string      `json:"port"`
@@ -172,19 +636,40 @@ type ServerConfig struct {
 	Environment  Environment `json:"environment"`
 	Platform     Platform    `json:"platform"`
 	EnableCORS   bool        `json:"enable_cors"`   // 웹 앱 지원
-	EnableWSS    bool        `json:"enable_wss"`    // WebSocket 지원
+	EnableWSS    bool        `json:"enable_wss"`    // WebSocket 지원 (websocket.enable과 함께 true여야 활성화)
 	StaticPath   string      `json:"static_path"`   // 정적 파일 경로
 	TemplatePath string      `json:"template_path"` // 템플릿 경로
 	LogLevel     LogLevel    `json:"log_level"`
+	LogFormat    string      `json:"log_format"` // "text"(사람이 읽는 형식), "json"(LogEntry 한 줄씩)
 	DebugMode    bool        `json:"debug_mode"`
 }
 
 // WebSocketConfig WebSocket 설정 (실시간 통신 지원)
 type WebSocketConfig struct {
 	Enable            bool   `json:"enable"`
-	Endpoint          string `json:"endpoint"`
-	MaxConnections    int    `json:"max_connections"`
-	HeartbeatInterval int    `json:"heartbeat_interval"` // 초 단위
+	Endpoint          string `json:"endpoint"`           // 예: "/api/ws"
+	MaxConnections    int    `json:"max_connections"`    // 동시 연결 수 (초과 시 503)
+	HeartbeatInterval int    `json:"heartbeat_interval"` // 초 단위 Ping 주기 (2주기 동안 응답이 없으면 연결 종료)
+}
+
+// WSMessage WebSocket 클라이언트 → 서버 메시지
+// Data는 같은 동작의 HTTP 엔드포인트 요청 본문과 같습니다.
+type WSMessage struct {
+	Type string          `json:"type"`           // "jog", "stop", "mode", "axis", "jog_start", "jog_heartbeat", "jog_stop"
+	ID   string          `json:"id,omitempty"`   // 응답 매칭용 요청 ID (응답에 그대로 포함)
+	Data json.RawMessage `json:"data,omitempty"` // 요청 본문
+}
+
+// WSEvent WebSocket 서버 → 클라이언트 메시지
+type WSEvent struct {
+	Type      string                 `json:"type"`                // "hello", "state", "state_delta", "state_error", "result", "error"
+	ID        string                 `json:"id,omitempty"`        // 요청 ID (result, error)
+	Seq       uint64                 `json:"seq,omitempty"`       // 상태 조회 순번 (state, state_delta)
+	Timestamp string                 `json:"timestamp,omitempty"` // 상태를 읽은 시각
+	State     *JogState              `json:"state,omitempty"`     // 전체 상태 (연결 직후, 재연결 후)
+	Changes   map[string]interface{} `json:"changes,omitempty"`   // 바뀐 항목만 (cartesian, joint, tool, status.*)
+	Data      interface{}            `json:"data,omitempty"`      // 응답 본문 (HTTP 엔드포인트 응답과 같음)
+	Message   string                 `json:"message,omitempty"`
 }
 
 // CORSConfig CORS 설정 (웹 앱 호환성)
@@ -218,6 +703,48 @@ type HealthCheck struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// HealthResponse /healthz, /readyz 응답
+type HealthResponse struct {
+	Status string        `json:"status"` // "ok", "ready", "not_ready"
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+// ClientLogRequest 브라우저 클라이언트 로그 (POST /client-log - 하나 또는 배열)
+type ClientLogRequest struct {
+	Level     string                 `json:"level"` // "error", "warn", "info", "debug"
+	Message   string                 `json:"message"`
+	Timestamp string                 `json:"timestamp,omitempty"` // 브라우저 시각
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Meta      RequestMeta            `json:"meta,omitempty"` // SessionID, TraceID로 서버 로그와 연결
+}
+
+// ClientLogEntry 저장된 클라이언트 로그 (GET /api/client-logs)
+type ClientLogEntry struct {
+	Time       string                 `json:"time"`                  // 서버 수신 시각
+	ClientTime string                 `json:"client_time,omitempty"` // 브라우저 시각
+	Level      string                 `json:"level"`
+	Message    string                 `json:"message"`
+	SessionID  string                 `json:"session_id,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+	ClientID   string                 `json:"client_id,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	RemoteAddr string                 `json:"remote_addr,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LogLevelRequest 실행 중 로그 레벨 변경 요청 (PUT /api/admin/loglevel)
+type LogLevelRequest struct {
+	Level string      `json:"level"` // "INFO", "DEBUG", "VERBOSE"
+	Meta  RequestMeta `json:"meta,omitempty"`
+}
+
+// LogLevelResponse 로그 레벨 조회/변경 응답
+type LogLevelResponse struct {
+	Level    LogLevel  `json:"level"`
+	Previous *LogLevel `json:"previous,omitempty"` // 변경 요청일 때 이전 레벨
+	Format   string    `json:"format"`
+}
+
 // LogEntry 로그 엔트리 (구조화된 로깅)
 type LogEntry struct {
 	Level     LogLevel               `json:"level"`
@@ -8,18 +8,37 @@
 package web
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strings"
 )
 
+// ============================================================================
+// 경로 설정 (Path Configuration)
+// ============================================================================
+
+// 웹 리소스 디렉터리 (Configure로 변경 가능)
+var (
+	staticDir   = filepath.FromSlash("web/static")
+	templateDir = filepath.FromSlash("web/templates")
+)
+
+// Configure 정적 파일 및 템플릿 디렉터리 설정 (서버 시작 전에 호출)
+func Configure(staticPath, templatePath string) {
+	staticDir = filepath.Clean(staticPath)
+	templateDir = filepath.Clean(templatePath)
+}
+
 // ============================================================================
 // 웹 인터페이스 핸들러 (Web Interface Handlers)
 // ============================================================================
 
 // InterfaceHandler 웹 인터페이스 템플릿 서빙 (외부 호출용)
 func InterfaceHandler(w http.ResponseWriter, r *http.Request) {
-	tmpl, err := template.ParseFiles("web/templates/index.html")
+	tmpl, err := template.ParseFiles(filepath.Join(templateDir, "index.html"))
 	if err != nil {
 		http.Error(w, "Template loading error: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -41,13 +60,40 @@ func InterfaceHandler(w http.ResponseWriter, r *http.Request) {
 func StaticFileHandler(w http.ResponseWriter, r *http.Request) {
 	// URL에서 /static/ 제거
 	file := r.URL.Path[len("/static/"):]
-	filePath := filepath.Join("web/static", file)
+	filePath := filepath.Join(staticDir, file)
 
 	// 보안을 위해 경로 검증
-	if filepath.Dir(filePath) != "web/static" && filepath.Dir(filePath) != "web\\static" {
+	if filepath.Dir(filePath) != staticDir {
 		http.Error(w, "Invalid path", http.StatusBadRequest)
 		return
 	}
 
 	http.ServeFile(w, r, filePath)
 }
+
+// ============================================================================
+// 상태 확인 (Health Checks)
+// ============================================================================
+
+// REQUIRED_STATIC_FILES 웹 인터페이스에 필요한 정적 파일
+var REQUIRED_STATIC_FILES = []string{"app.js", "style.css"}
+
+// CheckTemplates index.html 템플릿을 읽고 파싱할 수 있는지 확인
+func CheckTemplates() error {
+	_, err := template.ParseFiles(filepath.Join(templateDir, "index.html"))
+	return err
+}
+
+// CheckStaticAssets 필수 정적 파일이 있는지 확인
+func CheckStaticAssets() error {
+	var missing []string
+	for _, name := range REQUIRED_STATIC_FILES {
+		if stat, err := os.Stat(filepath.Join(staticDir, name)); err != nil || stat.IsDir() {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s에 없는 파일: %s", staticDir, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,433 @@
+// ============================================================================
+// internal/websocket/websocket.go - 표준 라이브러리 WebSocket (RFC 6455) 서버
+// ============================================================================
+// 외부 의존성 없이 WebSocket 핸드셰이크와 프레임 송수신을 구현합니다.
+// 서버 쪽에 필요한 기능만 지원합니다.
+//
+// 지원 범위:
+// - HTTP/1.1 Upgrade 핸드셰이크 (Sec-WebSocket-Version 13)
+// - 텍스트/바이너리 메시지, 조각난(fragmented) 메시지 재조립
+// - Ping 자동 응답, Pong 수신 시 읽기 기한 연장
+// - Close 핸드셰이크 (상태 코드와 사유)
+// - 확장(permessage-deflate 등)과 서브프로토콜은 지원하지 않음
+// ============================================================================
+
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// 상수 정의 (Constants)
+// ============================================================================
+
+// 메시지 종류 (opcode)
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// Close 상태 코드
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	CloseTryAgainLater   = 1013
+)
+
+// 기본값
+const (
+	DEFAULT_MAX_MESSAGE_SIZE = 64 * 1024 // 수신 메시지 최대 크기 (바이트)
+	DEFAULT_WRITE_TIMEOUT    = 5 * time.Second
+	maxControlPayload        = 125
+	websocketGUID            = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// ErrClosed 이미 닫힌 연결
+var ErrClosed = errors.New("websocket: 연결이 닫혔습니다")
+
+// CloseError 상대방이 보낸 Close 프레임 또는 프로토콜 오류로 닫힌 경우
+type CloseError struct {
+	Code int
+	Text string
+}
+
+// Error error 인터페이스 구현
+func (e *CloseError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("websocket: 연결 종료 (%d)", e.Code)
+	}
+	return fmt.Sprintf("websocket: 연결 종료 (%d): %s", e.Code, e.Text)
+}
+
+// ============================================================================
+// 핸드셰이크 (Handshake)
+// ============================================================================
+
+// Options 핸드셰이크 및 연결 옵션
+type Options struct {
+	MaxMessageSize int64                      // 수신 메시지 최대 크기 (0이면 기본값)
+	WriteTimeout   time.Duration              // 프레임 전송 기한 (0이면 기본값)
+	CheckOrigin    func(r *http.Request) bool // nil이면 같은 호스트의 Origin만 허용
+}
+
+// Upgrade HTTP 요청을 WebSocket 연결로 전환
+// 실패하면 HTTP 오류 응답을 이미 보낸 상태로 에러를 반환합니다.
+func Upgrade(w http.ResponseWriter, r *http.Request, opts Options) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return nil, errors.New("websocket: GET 요청이 아닙니다")
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: Upgrade 헤더가 없습니다")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errors.New("websocket: 지원하지 않는 버전")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "Invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: 잘못된 Sec-WebSocket-Key")
+	}
+
+	checkOrigin := opts.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "Forbidden origin", http.StatusForbidden)
+		return nil, fmt.Errorf("websocket: 허용되지 않은 Origin: %s", r.Header.Get("Origin"))
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: http.Hijacker를 지원하지 않는 ResponseWriter")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("websocket: hijack 실패: %w", err)
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	if opts.MaxMessageSize <= 0 {
+		opts.MaxMessageSize = DEFAULT_MAX_MESSAGE_SIZE
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DEFAULT_WRITE_TIMEOUT
+	}
+	netConn.SetDeadline(time.Time{})
+	return &Conn{conn: netConn, br: rw.Reader, opts: opts}, nil
+}
+
+// acceptKey Sec-WebSocket-Accept 값 계산
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerHasToken 콤마로 구분된 헤더 값에 token이 있는지 확인 (대소문자 무시)
+func headerHasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin Origin이 없거나 요청 호스트와 같으면 허용 (다른 사이트의 페이지가 JOG 명령을 보내지 못하도록)
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// ============================================================================
+// 연결 (Connection)
+// ============================================================================
+
+// Conn WebSocket 연결 - 읽기는 하나의 고루틴에서만, 쓰기는 여러 고루틴에서 호출 가능
+type Conn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	opts Options
+
+	readTimeout time.Duration // 프레임 사이 최대 대기 (0이면 무제한)
+
+	writeMu   sync.Mutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+// RemoteAddr 원격 주소
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetReadTimeout 프레임(Pong 포함)을 받을 때마다 읽기 기한을 timeout만큼 연장
+// 하트비트 Ping에 응답하지 않는 클라이언트를 끊는 데 사용합니다.
+func (c *Conn) SetReadTimeout(timeout time.Duration) {
+	c.readTimeout = timeout
+}
+
+// ReadMessage 다음 데이터 메시지 수신 (Ping/Pong/Close는 내부에서 처리)
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		messageOp int
+		message   []byte
+	)
+	for {
+		if c.readTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+		}
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, c.failRead(err)
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.writeFrame(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			// 받은 상태 코드를 그대로 돌려줌 (1005는 전송할 수 없는 코드라 1000으로)
+			closeErr := parseClosePayload(payload)
+			echo := closeErr.Code
+			if echo == CloseNoStatus {
+				echo = CloseNormal
+			}
+			c.closeWith(echo, "")
+			return 0, nil, closeErr
+		case OpText, OpBinary:
+			if messageOp != 0 {
+				return 0, nil, c.failRead(&CloseError{Code: CloseProtocolError, Text: "이전 메시지가 끝나기 전에 새 메시지 시작"})
+			}
+			messageOp = op
+		case OpContinuation:
+			if messageOp == 0 {
+				return 0, nil, c.failRead(&CloseError{Code: CloseProtocolError, Text: "시작 프레임 없는 continuation"})
+			}
+		default:
+			return 0, nil, c.failRead(&CloseError{Code: CloseProtocolError, Text: fmt.Sprintf("알 수 없는 opcode %d", op)})
+		}
+
+		if int64(len(message))+int64(len(payload)) > c.opts.MaxMessageSize {
+			return 0, nil, c.failRead(&CloseError{Code: CloseMessageTooBig, Text: "메시지가 너무 큽니다"})
+		}
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+
+		if messageOp == OpText && !utf8.Valid(message) {
+			return 0, nil, c.failRead(&CloseError{Code: CloseInvalidPayload, Text: "UTF-8이 아닌 텍스트"})
+		}
+		return messageOp, message, nil
+	}
+}
+
+// readFrame 프레임 하나 읽기 (클라이언트 프레임은 반드시 마스킹)
+func (c *Conn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.br, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	op = int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 {
+		err = &CloseError{Code: CloseProtocolError, Text: "지원하지 않는 확장 비트"}
+		return
+	}
+	if header[1]&0x80 == 0 {
+		err = &CloseError{Code: CloseProtocolError, Text: "마스킹되지 않은 클라이언트 프레임"}
+		return
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if op >= OpClose && (!fin || length > maxControlPayload) {
+		err = &CloseError{Code: CloseProtocolError, Text: "잘못된 제어 프레임"}
+		return
+	}
+	if length < 0 || length > c.opts.MaxMessageSize {
+		err = &CloseError{Code: CloseMessageTooBig, Text: "메시지가 너무 큽니다"}
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// failRead 읽기 오류 처리 - 프로토콜 오류면 Close 프레임을 보내고 연결 종료
+func (c *Conn) failRead(err error) error {
+	var closeErr *CloseError
+	if errors.As(err, &closeErr) {
+		c.closeWith(closeErr.Code, closeErr.Text)
+		return err
+	}
+	c.conn.Close()
+	return err
+}
+
+// parseClosePayload Close 프레임 본문에서 상태 코드와 사유 추출
+func parseClosePayload(payload []byte) *CloseError {
+	if len(payload) < 2 {
+		return &CloseError{Code: CloseNoStatus}
+	}
+	return &CloseError{Code: int(binary.BigEndian.Uint16(payload[:2])), Text: string(payload[2:])}
+}
+
+// WriteMessage 데이터 메시지 전송 (OpText 또는 OpBinary)
+func (c *Conn) WriteMessage(op int, data []byte) error {
+	return c.writeFrame(op, data)
+}
+
+// WriteJSON v를 JSON 텍스트 메시지로 전송
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(OpText, data)
+}
+
+// Ping 하트비트 Ping 전송 (브라우저는 자동으로 Pong 응답)
+func (c *Conn) Ping() error {
+	return c.writeFrame(OpPing, nil)
+}
+
+// Close 정상 종료 (Close 프레임 전송 후 연결 닫기)
+func (c *Conn) Close() error {
+	return c.CloseWithReason(CloseNormal, "")
+}
+
+// CloseWithReason 상태 코드와 사유를 보내고 연결 닫기
+func (c *Conn) CloseWithReason(code int, reason string) error {
+	c.closeWith(code, reason)
+	return nil
+}
+
+// closeWith Close 프레임을 한 번만 보내고 연결 닫기
+func (c *Conn) closeWith(code int, reason string) {
+	c.closeOnce.Do(func() {
+		if len(reason) > maxControlPayload-2 {
+			reason = reason[:maxControlPayload-2]
+		}
+		payload := make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+		c.writeFrame(OpClose, payload)
+
+		c.writeMu.Lock()
+		c.closed = true
+		c.writeMu.Unlock()
+		c.conn.Close()
+	})
+}
+
+// writeFrame 프레임 하나 전송 (서버 프레임은 마스킹하지 않음)
+func (c *Conn) writeFrame(op int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | byte(op)
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
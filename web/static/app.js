@@ -2,6 +2,7 @@
 // * HTML5 Konva.js를 사용한 로봇팔 시각화 및 제어
 
 let currentJogMode = 'joint'; // * 전역 변수로 현재 모드 추적
+let robotModel = null;        // * 서버 로봇 모델 (/api/model - 축, 모드 정의)
 
 // * SCARA 로봇팔 시각화 관련 변수
 let stage, layer, robotArm;
@@ -264,41 +265,98 @@ function updateJointAngles(jointValues) {
 }
 
 
-// Get current jog mode from select
+// * 로봇 모델 로드 - 모드 버튼과 축 목록을 모델 정의로 생성
+function loadRobotModel() {
+	fetch('/api/model')
+		.then(response => response.json())
+		.then(model => {
+			robotModel = model;
+			console.log('🦾 로봇 모델:', model.name, model);
+			renderModeButtons();
+			updateAxisOptions(false); // 페이지 로드만으로 컨트롤러에 쓰지 않음
+		})
+		.catch(error => {
+			console.error('로봇 모델 로드 실패 (기본 축 목록 사용):', error);
+		});
+}
+
+// 모델의 모드 테이블로 모드 버튼 생성
+function renderModeButtons() {
+	const container = document.getElementById('mode-buttons');
+	container.innerHTML = '';
+	robotModel.modes.forEach(mode => {
+		const key = mode.name.toLowerCase();
+		const button = document.createElement('button');
+		button.type = 'button';
+		button.className = 'mode-btn' + (key === currentJogMode ? ' active' : '');
+		button.id = 'btn-' + key;
+		button.textContent = mode.name;
+		button.onclick = () => setJogModeButton(key);
+		container.appendChild(button);
+	});
+}
+
+// 현재 모드 정의 (모델이 없으면 null)
+function getCurrentModeInfo() {
+	if (!robotModel) {
+		return null;
+	}
+	return robotModel.modes.find(mode => mode.name.toLowerCase() === currentJogMode) || null;
+}
+
+// 현재 모드의 JOG 축 집합 ("joint", "cartesian", 축 선택이 없는 모드는 "")
 function getSelectedMode() {
-	return document.getElementById('modeSelect').value;
+	const info = getCurrentModeInfo();
+	if (info) {
+		return info.axes || '';
+	}
+	return currentJogMode === 'joint' ? 'joint' : 'cartesian';
+}
+
+// 현재 모드에서 선택 가능한 축 목록 (모델이 없으면 기본 6축)
+function getModeAxes(mode) {
+	if (robotModel) {
+		if (mode === 'joint') return robotModel.joints;
+		if (mode === 'cartesian') return robotModel.cartesian;
+		return [];
+	}
+	if (mode === 'joint') {
+		return [1, 2, 3, 4, 5, 6].map(n => ({ name: 'J' + n, aliases: ['joint' + n] }));
+	}
+	return ['X', 'Y', 'Z', 'Rx', 'Ry', 'Rz'].map(name => ({ name: name, aliases: [name.toLowerCase()] }));
 }
 
-// Handle mode change from select
+// Handle mode change from buttons
 function setJogModeButton(mode) {
 	// Update current mode
 	currentJogMode = mode;
+	document.querySelectorAll('.mode-btn').forEach(button => {
+		button.classList.toggle('active', button.id === 'btn-' + mode);
+	});
 	// Send mode change to robot
 	setJogMode(mode);
 	// Update axis options
 	updateAxisOptions();
 }
 
-function updateAxisOptions() {
+function updateAxisOptions(sendSelection = true) {
 	const axisSelect = document.getElementById('axisSelect');
 	const mode = getSelectedMode();
+	const axes = getModeAxes(mode);
+
+	axisSelect.innerHTML = '';
+	axes.forEach(axis => {
+		const option = document.createElement('option');
+		option.value = axis.aliases[0];
+		option.textContent = axis.name + (axis.unit ? ' (' + axis.unit + ')' : '');
+		axisSelect.appendChild(option);
+	});
+	axisSelect.selectedIndex = axes.length > 0 ? 0 : -1;
+	document.getElementById('selectedAxis').textContent = axes.length > 0 ? axes[0].name : '축 선택 없음';
 
-	if (mode === 'joint') {
-		axisSelect.innerHTML =
-			'<option value="joint1">Joint 1</option>' +
-			'<option value="joint2">Joint 2</option>' +
-			'<option value="joint3">Joint 3</option>' +
-			'<option value="joint4">Joint 4</option>' +
-			'<option value="joint5">Joint 5</option>' +
-			'<option value="joint6">Joint 6</option>';
-	} else {
-		axisSelect.innerHTML =
-			'<option value="x">X axis</option>' +
-			'<option value="y">Y axis</option>' +
-			'<option value="z">Z axis</option>' +
-			'<option value="rx">Rx rotation</option>' +
-			'<option value="ry">Ry rotation</option>' +
-			'<option value="rz">Rz rotation</option>';
+	// 축 선택이 없는 모드(Computer, Free)는 축 선택 전송 생략
+	if (axes.length === 0 || !sendSelection) {
+		return;
 	}
 
 	// Send initial axis selection after updating options
@@ -327,35 +385,19 @@ function jogListChanged() {
 	// const selectedAxisSpan = document.getElementById('selectedAxis');
 	const mode = getSelectedMode();
 
-	// 선택된 축 이름 표시 업데이트
-	const axisNames = {
-		'joint1': 'Joint 1', 'joint2': 'Joint 2', 'joint3': 'Joint 3',
-		'joint4': 'Joint 4', 'joint5': 'Joint 5', 'joint6': 'Joint 6',
-		'x': 'X축', 'y': 'Y축', 'z': 'Z축',
-		'rx': 'Rx 회전', 'ry': 'Ry 회전', 'rz': 'Rz 회전'
-	};
-
-	// No UI update here; position panel shows current-axis
-
-	// 축 번호 계산
-	let axisNumber = 1;
-	if (mode === 'joint') {
-		axisNumber = parseInt(selectedAxis.replace('joint', ''));
-	} else {
-		const cartesianMap = { 'x': 1, 'y': 2, 'z': 3, 'rx': 4, 'ry': 5, 'rz': 6 };
-		axisNumber = cartesianMap[selectedAxis] || 1;
+	// 축 번호 = 모델 축 목록에서의 위치 (1부터)
+	const axes = getModeAxes(mode);
+	const index = axes.findIndex(axis => axis.aliases.includes(selectedAxis));
+	const axisNumber = index >= 0 ? index + 1 : 1;
+	if (index >= 0) {
+		document.getElementById('selectedAxis').textContent = axes[index].name;
 	}
 
 	// 로봇에 축 선택 전송
-	fetch('/api/jog/axis', {
-		method: 'POST',
-		headers: { 'Content-Type': 'application/json' },
-		body: JSON.stringify({
-			axis: axisNumber,
-			robot: 1
-		})
+	sendCommand('/api/jog/axis', 'axis', {
+		axis: axisNumber,
+		robot: 1
 	})
-		.then(response => response.json())
 		.then(data => {
 			console.log('축 선택 응답:', data);
 			if (!data.success) {
@@ -372,12 +414,7 @@ function jogListChanged() {
 
 function setJogMode(mode) {
 	// 로봇에 모드 변경 전송
-	fetch('/api/jog/mode', {
-		method: 'POST',
-		headers: { 'Content-Type': 'application/json' },
-		body: JSON.stringify({ mode: mode })
-	})
-		.then(response => response.json())
+	sendCommand('/api/jog/mode', 'mode', { mode: mode })
 		.then(data => {
 			console.log('모드 변경 응답:', data);
 			if (data.success) {
@@ -423,7 +460,9 @@ function sendJog(axis, direction) {
 		axis: axis,
 		dir: direction,
 		step: stepInput,
-		mode: mode
+		mode: mode,
+		seq: nextJogSeq(),
+		client_session: CLIENT_SESSION
 	};
 
 	// 디버깅: 상세한 명령 전송 로그
@@ -444,23 +483,11 @@ function sendJog(axis, direction) {
 	const fetchStartTime = performance.now();
 
 	// 서버 응답을 기다리지 않고 즉시 전송 (Fire and Forget 방식)
-	fetch('/api/jog', {
-		method: 'POST',
-		headers: {
-			'Content-Type': 'application/json',
-		},
-		body: JSON.stringify(command)
-	})
-		.then(response => {
-			if (!response.ok) {
-				throw new Error('HTTP ' + response.status + ' ' + response.statusText);
-			}
-			return response.json();
-		})
+	sendCommand('/api/jog', 'jog', command)
 		.then(data => {
 			const responseTime = performance.now() - fetchStartTime;
 
-			console.log('✅ JOG 응답:', {
+			console.log(data.success ? '✅ JOG 응답:' : '⚠️ JOG 실패 응답:', {
 				response: data,
 				responseTime: responseTime.toFixed(1) + 'ms',
 				commandNumber: jogCommandCount,
@@ -473,7 +500,7 @@ function sendJog(axis, direction) {
 					document.getElementById('status').textContent = '✅ ' + data.message;
 					document.getElementById('status').style.background = '#d4edda';
 				} else {
-					document.getElementById('status').textContent = '❌ ' + data.message;
+					document.getElementById('status').textContent = '❌ ' + data.message + (data.error_code ? ' [' + data.error_code + ']' : '');
 					document.getElementById('status').style.background = '#f8d7da';
 				}
 			}
@@ -496,67 +523,220 @@ function sendJog(axis, direction) {
 		});
 }
 
+// * 위치 새로고침 (HTTP) - WebSocket이 연결되어 있으면 상태가 자동으로 갱신됨
 function updatePosition() {
 	fetch('/api/jog/state')
 		.then(response => response.json())
-		.then(data => {
-			// 위치 정보 업데이트
-			let coordsText = '';
-			// Extract properties from JSON response
-			const joints = data.joint;
-			const carts = data.cartesian;
-			coordsText += '🦾 조인트: ' + joints.map((v, i) => 'J' + (i + 1) + '=' + v.toFixed(3) + '°').join(', ') + '\n';
-			coordsText += '📐 카르테시안: X=' + carts[0].toFixed(3) + ', Y=' + carts[1].toFixed(3) + ', Z=' + carts[2].toFixed(3) + '\n';
-			coordsText += '🔄 회전: Rx=' + carts[3].toFixed(3) + '°, Ry=' + carts[4].toFixed(3) + '°, Rz=' + carts[5].toFixed(3) + '°\n';
-			const stat = data.status;
-			coordsText += '⚙️  상태: 축수=' + stat.axis_count + ', 조깅=' + stat.allow_jog + ', 모드=' + stat.jog_mode;
-
-			document.getElementById('coordinates').textContent = coordsText;
-
-			// 로봇팔 시각화 업데이트
-			updateJointAngles(data.joint);
-
-			// 실시간 상태 정보 업데이트
-			document.getElementById('current-jog-mode').textContent = data.status.jog_mode_text + ' (' + data.status.jog_mode + ')';
-			document.getElementById('current-axis').textContent = data.status.selected_axis_text + ' (축' + data.status.selected_axis + ')';
-			document.getElementById('power-state').textContent = data.status.power_state;
-			document.getElementById('axis-count').textContent = data.status.axis_count;
-			document.getElementById('allow-jog').textContent = data.status.allow_jog ? '허용' : '금지';
-			document.getElementById('error-desc').textContent = data.status.error_desc || '없음';
-
-			// 상태에 따른 색상 변경
-			const jogModeElement = document.getElementById('current-jog-mode');
-			const allowJogElement = document.getElementById('allow-jog');
-
-			if (data.status.allow_jog) {
-				allowJogElement.style.color = '#28a745';
-				allowJogElement.style.fontWeight = 'bold';
+		.then(data => renderState(data))
+		.catch(error => renderStateError(error));
+}
+
+// * 로봇 상태 표시 (HTTP 응답과 WebSocket state 이벤트 공용)
+function renderState(data) {
+	// 위치 정보 업데이트
+	let coordsText = '';
+	// Extract properties from JSON response
+	const joints = data.joint;
+	const carts = data.cartesian;
+	const jointAxes = robotModel ? robotModel.joints : joints.map((v, i) => ({ name: 'J' + (i + 1), unit: 'deg' }));
+	coordsText += '🦾 조인트: ' + jointAxes.map((axis, i) => axis.name + '=' + joints[i].toFixed(3) + (axis.unit === 'mm' ? 'mm' : '°')).join(', ') + '\n';
+	coordsText += '📐 카르테시안: X=' + carts[0].toFixed(3) + ', Y=' + carts[1].toFixed(3) + ', Z=' + carts[2].toFixed(3) + '\n';
+	coordsText += '🔄 회전: Rx=' + carts[3].toFixed(3) + '°, Ry=' + carts[4].toFixed(3) + '°, Rz=' + carts[5].toFixed(3) + '°\n';
+	const stat = data.status;
+	coordsText += '⚙️  상태: 축수=' + stat.axis_count + ', 조깅=' + stat.allow_jog + ', 모드=' + stat.jog_mode;
+	const kin = data.kinematics;
+	if (kin && kin.valid) {
+		coordsText += '\n🏃 조인트 속도: ' + jointAxes.map((axis, i) => axis.name + '=' + (kin.joint_velocity[i] || 0).toFixed(2)).join(', ') + (kin.moving ? ' (움직이는 중)' : ' (정지)');
+	}
+
+	document.getElementById('coordinates').textContent = coordsText;
+	renderSelectedAxisSpeed(kin);
+
+	// 로봇팔 시각화 업데이트
+	updateJointAngles(data.joint);
+
+	// 실시간 상태 정보 업데이트
+	document.getElementById('current-jog-mode').textContent = data.status.jog_mode_text + ' (' + data.status.jog_mode + ')';
+	document.getElementById('current-axis').textContent = data.status.selected_axis_text + ' (축' + data.status.selected_axis + ')';
+	document.getElementById('power-state').textContent = data.status.power_state;
+	document.getElementById('axis-count').textContent = data.status.axis_count;
+	document.getElementById('allow-jog').textContent = data.status.allow_jog ? '허용' : '금지';
+	document.getElementById('error-desc').textContent = data.status.error_desc || '없음';
+	document.getElementById('connection-state').textContent = data.status.connection_state || (data.status.is_connected ? 'connected' : '알 수 없음');
+	document.getElementById('connection-state').style.color = data.status.is_connected ? '#28a745' : '#dc3545';
+	// 상태 데이터 감시 - 컨트롤러 응답이 멈췄거나 조회가 늦으면 위치를 믿지 않도록 표시
+	if (data.meta && data.meta.stale) {
+		document.getElementById('connection-state').textContent += ' ⚠️ 데이터 stale (' + data.meta.stale_reason + ')';
+		document.getElementById('connection-state').style.color = '#fd7e14';
+	}
+
+	// 상태에 따른 색상 변경
+	const jogModeElement = document.getElementById('current-jog-mode');
+	const allowJogElement = document.getElementById('allow-jog');
+
+	if (data.status.allow_jog) {
+		allowJogElement.style.color = '#28a745';
+		allowJogElement.style.fontWeight = 'bold';
+	} else {
+		allowJogElement.style.color = '#dc3545';
+		allowJogElement.style.fontWeight = 'bold';
+	}
+
+	// JOG 모드에 따른 색상
+	switch (data.status.jog_mode) {
+		case 1:
+			jogModeElement.style.color = '#007bff'; // Joint - 파란색
+			break;
+		case 2:
+			jogModeElement.style.color = '#28a745'; // World - 초록색
+			break;
+		case 3:
+			jogModeElement.style.color = '#fd7e14'; // Tool - 주황색
+			break;
+		default:
+			jogModeElement.style.color = '#6c757d'; // 기본 - 회색
+	}
+}
+
+// * 상태 조회 실패 표시
+// * 선택한 축의 속도 표시 (JOG 버튼 옆)
+function renderSelectedAxisSpeed(kin) {
+	const el = document.getElementById('selectedAxisSpeed');
+	if (!el) return;
+	if (!kin || !kin.valid) {
+		el.textContent = '속도 -';
+		return;
+	}
+	const mode = getSelectedMode();
+	const axes = getModeAxes(mode);
+	const index = axes.findIndex(axis => axis.aliases.includes(getSelectedAxis()));
+	const velocities = mode === 'joint' ? kin.joint_velocity : kin.cartesian_velocity;
+	if (index < 0 || !velocities || index >= velocities.length) {
+		el.textContent = '속도 -';
+		return;
+	}
+	const unit = axes[index].unit === 'mm' ? 'mm/s' : '°/s';
+	el.textContent = '속도 ' + velocities[index].toFixed(2) + ' ' + unit;
+	el.style.color = kin.moving ? '#fd7e14' : '#6c757d';
+}
+
+function renderStateError(error) {
+	console.error('위치 정보 업데이트 실패:', error);
+	document.getElementById('coordinates').textContent = '❌ 위치 정보 로딩 실패: ' + error;
+	document.getElementById('current-jog-mode').textContent = '연결 오류';
+	document.getElementById('current-axis').textContent = '연결 오류';
+	updateConnectionState();
+}
+
+// * 상태 조회 실패 시 컨트롤러 연결 상태를 별도로 조회
+function updateConnectionState() {
+	fetch('/api/connection')
+		.then(response => response.json())
+		.then(conn => {
+			const el = document.getElementById('connection-state');
+			el.textContent = conn.state + (conn.last_error_message ? ' (' + conn.last_error_message + ')' : '');
+			el.style.color = conn.connected ? '#28a745' : '#dc3545';
+		})
+		.catch(() => {
+			document.getElementById('connection-state').textContent = '서버 응답 없음';
+		});
+}
+
+// * WebSocket 상태 스트림 + 명령 채널
+// 연결되어 있으면 명령을 소켓으로 보내고 상태 변화를 받아 표시합니다.
+// 연결되지 않았거나 서버에서 비활성화되어 있으면 기존 HTTP 요청을 사용합니다.
+const WS_ENDPOINT = '/api/ws';      // 서버 websocket.endpoint와 같아야 함
+const WS_RECONNECT_DELAY = 3000;    // ms
+let ws = null;
+let wsReady = false;
+let wsRequestId = 0;
+const wsPending = new Map();        // 요청 ID → { resolve, reject }
+let lastState = null;               // state_delta를 적용할 마지막 전체 상태
+
+function connectWebSocket() {
+	const url = (location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + WS_ENDPOINT;
+	ws = new WebSocket(url);
+
+	ws.onopen = () => {
+		wsReady = true;
+		console.log('🔌 WebSocket 연결:', url);
+	};
+	ws.onmessage = event => handleWSEvent(JSON.parse(event.data));
+	ws.onclose = event => {
+		wsReady = false;
+		lastState = null;
+		// 응답을 기다리던 요청은 실패 처리 (서버가 이 연결의 JOG를 중단함)
+		wsPending.forEach(pending => pending.reject(new Error('WebSocket 연결 종료')));
+		wsPending.clear();
+		console.warn('🔌 WebSocket 종료 (' + event.code + ') - HTTP 사용, ' + WS_RECONNECT_DELAY + 'ms 후 재연결');
+		setTimeout(connectWebSocket, WS_RECONNECT_DELAY);
+	};
+}
+
+function handleWSEvent(msg) {
+	switch (msg.type) {
+		case 'hello':
+			console.log('🔌 WebSocket 준비:', msg.data);
+			break;
+		case 'state':
+			lastState = msg.state;
+			renderState(lastState);
+			break;
+		case 'state_delta': {
+			if (!lastState) return;
+			const changes = msg.changes;
+			['cartesian', 'joint', 'tool', 'kinematics'].forEach(key => {
+				if (key in changes) lastState[key] = changes[key];
+			});
+			if (changes.status) Object.assign(lastState.status, changes.status);
+			if (changes.meta) Object.assign(lastState.meta, changes.meta);
+			lastState.meta.seq = msg.seq;
+			lastState.meta.timestamp = msg.timestamp;
+			renderState(lastState);
+			break;
+		}
+		case 'state_error':
+			lastState = null;
+			renderStateError(msg.message);
+			break;
+		case 'result':
+		case 'error': {
+			const pending = wsPending.get(msg.id);
+			if (!pending) {
+				if (msg.type === 'error') console.error('❌ WebSocket 오류:', msg.message);
+				return;
+			}
+			wsPending.delete(msg.id);
+			if (msg.type === 'result') {
+				pending.resolve(msg.data);
 			} else {
-				allowJogElement.style.color = '#dc3545';
-				allowJogElement.style.fontWeight = 'bold';
+				pending.reject(new Error(msg.message));
 			}
+			break;
+		}
+	}
+}
 
-			// JOG 모드에 따른 색상
-			switch (data.status.jog_mode) {
-				case 1:
-					jogModeElement.style.color = '#007bff'; // Joint - 파란색
-					break;
-				case 2:
-					jogModeElement.style.color = '#28a745'; // World - 초록색
-					break;
-				case 3:
-					jogModeElement.style.color = '#fd7e14'; // Tool - 주황색
-					break;
-				default:
-					jogModeElement.style.color = '#6c757d'; // 기본 - 회색
-			}
-		})
-		.catch(error => {
-			console.error('위치 정보 업데이트 실패:', error);
-			document.getElementById('coordinates').textContent = '❌ 위치 정보 로딩 실패: ' + error;
-			document.getElementById('current-jog-mode').textContent = '연결 오류';
-			document.getElementById('current-axis').textContent = '연결 오류';
+// * 명령 전송 - WebSocket이 연결되어 있으면 소켓으로, 아니면 HTTP POST (응답 본문은 같음)
+function sendCommand(url, type, body) {
+	if (wsReady) {
+		const id = String(++wsRequestId);
+		return new Promise((resolve, reject) => {
+			wsPending.set(id, { resolve, reject });
+			ws.send(JSON.stringify({ type: type, id: id, data: body }));
+		});
+	}
+
+	return fetch(url, {
+		method: 'POST',
+		headers: { 'Content-Type': 'application/json' },
+		body: JSON.stringify(body)
+	}).then(response => {
+		// 실패 응답도 JSON(JogResponse 등)이므로 메시지와 error_code를 그대로 사용
+		return response.json().catch(() => {
+			throw new Error('HTTP ' + response.status + ' ' + response.statusText);
 		});
+	});
 }
 
 // 🔍 네트워크 신호 캡처용 Fetch 인터셉터 추가
@@ -585,79 +765,218 @@ function updatePosition() {
 })();
 
 // * 연속 조깅을 위한 변수들
-let continuousJogInterval = null;
+// 반복 전송은 서버의 JOG 세션이 담당하고, 브라우저는 하트비트만 보냄
+// (하트비트가 끊기면 서버가 자동으로 중단 명령 전송 - 데드맨)
+const JOG_HEARTBEAT_INTERVAL = 100;  // ms (서버 하트비트 창보다 충분히 짧게)
+let jogHeartbeatInterval = null;
+let jogSessionStart = null;  // 세션 시작 요청 Promise (세션 ID 또는 null)
+let jogDirection = null;
 let isJogging = false;
 let keyBusy = false;  // 키 중복 방지를 위한 플래그 (원본 방식)
 
+// 명령 순서 보장: 탭마다 고유한 클라이언트 세션과 단조 증가 시퀀스 번호
+const CLIENT_SESSION = 'tab-' + Date.now().toString(36) + '-' + Math.random().toString(36).slice(2, 8);
+let jogSeq = 0;
+
+function nextJogSeq() {
+	return ++jogSeq;
+}
+
+// 🌐 클라이언트 로그 전송: console.error/warn을 모아 서버(/client-log)에 저장
+// 작업자가 문제를 알린 뒤 /api/client-logs?session=<CLIENT_SESSION>으로 조회
+// sendBeacon을 사용하므로 위 fetch 인터셉터를 거치지 않음 (전송 오류가 다시 전송되지 않음)
+const CLIENT_LOG_ENDPOINT = '/client-log';
+const CLIENT_LOG_FLUSH_INTERVAL = 2000;  // ms
+const CLIENT_LOG_MAX_BATCH = 50;         // 서버 최대 100개
+let clientLogQueue = [];
+
+function queueClientLog(level, args) {
+	const message = args.map(arg => {
+		if (arg instanceof Error) return arg.message;
+		if (typeof arg === 'string') return arg;
+		try {
+			return JSON.stringify(arg);
+		} catch (_) {
+			return String(arg);
+		}
+	}).join(' ');
+
+	clientLogQueue.push({
+		level: level,
+		message: message,
+		timestamp: new Date().toISOString(),
+		meta: { session_id: CLIENT_SESSION, client_id: CLIENT_SESSION }
+	});
+	if (clientLogQueue.length >= CLIENT_LOG_MAX_BATCH) flushClientLogs();
+}
+
+function flushClientLogs() {
+	if (clientLogQueue.length === 0 || !navigator.sendBeacon) {
+		clientLogQueue = [];
+		return;
+	}
+	const batch = clientLogQueue.splice(0, CLIENT_LOG_MAX_BATCH);
+	navigator.sendBeacon(CLIENT_LOG_ENDPOINT, new Blob([JSON.stringify(batch)], { type: 'application/json' }));
+}
+
+['error', 'warn'].forEach(level => {
+	const original = console[level].bind(console);
+	console[level] = function (...args) {
+		original(...args);
+		queueClientLog(level, args);
+	};
+});
+window.addEventListener('error', event => queueClientLog('error', [event.message, event.filename + ':' + event.lineno]));
+window.addEventListener('unhandledrejection', event => queueClientLog('error', ['Unhandled rejection:', event.reason]));
+window.addEventListener('pagehide', flushClientLogs);
+setInterval(flushClientLogs, CLIENT_LOG_FLUSH_INTERVAL);
+
 // 성능 측정 변수들
 let jogStartTime = 0;
 let jogCommandCount = 0;
 let lastJogTime = 0;
 
-// * 연속 조깅 시작 함수 - 원본 방식 개선
+// * 연속 조깅 시작 함수 - 서버 측 JOG 세션 사용
 function startContinuousJog(direction) {
 	const currentTime = performance.now();
-	jogStartTime = currentTime;
-	jogCommandCount = 0;
 
-	console.log('🚀 연속 조깅 시작:', {
-		direction: direction,
-		startTime: new Date().toLocaleTimeString() + '.' + (currentTime % 1000).toFixed(0).padStart(3, '0'),
-		timestamp: currentTime
-	});
+	// 같은 방향으로 이미 조깅 중이면 세션 유지 (마우스 휠 연속 입력)
+	if (isJogging && jogDirection === direction) {
+		return;
+	}
 
-	// 이미 조깅 중이면 먼저 중단
+	// 다른 방향으로 조깅 중이면 먼저 중단
 	if (isJogging) {
 		console.log('⚠️  이미 조깅 중 - 기존 조깅 중단');
 		stopContinuousJog();
 	}
 
+	const stepInput = parseFloat(document.getElementById('stepSize').value);
+	if (isNaN(stepInput) || stepInput < 0.1 || stepInput > 10) {
+		document.getElementById('status').textContent = '❌ 잘못된 스텝 크기: ' + stepInput;
+		document.getElementById('status').style.background = '#f8d7da';
+		return;
+	}
+
+	jogStartTime = currentTime;
+	jogCommandCount = 0;
+	jogDirection = direction;
 	isJogging = true;
 
-	// 즉시 첫 번째 조깅 실행 (딜레이 없이)
-	sendSelectedAxisJog(direction);
+	const command = {
+		axis: getSelectedAxis(),
+		dir: direction,
+		step: stepInput,
+		mode: getSelectedMode(),
+		seq: nextJogSeq(),
+		client_session: CLIENT_SESSION
+	};
+
+	console.log('🚀 연속 조깅 세션 시작:', {
+		command: command,
+		startTime: new Date().toLocaleTimeString() + '.' + (currentTime % 1000).toFixed(0).padStart(3, '0')
+	});
 
-	// 연속 조깅을 위한 인터벌 시작 (30ms 간격으로 더 빠른 반응)
-	continuousJogInterval = setInterval(() => {
-		if (isJogging) {
-			sendSelectedAxisJog(direction);
-		}
-	}, 30);
+	jogSessionStart = sendCommand('/api/jog/session/start', 'jog_start', command)
+		.then(data => {
+			if (!data.success) {
+				console.warn('⚠️ 연속 조깅 세션 시작 실패:', data);
+				document.getElementById('status').textContent = '❌ ' + data.message + (data.error_code ? ' [' + data.error_code + ']' : '');
+				document.getElementById('status').style.background = '#f8d7da';
+				return null;
+			}
+			console.log('✅ 연속 조깅 세션:', data.session);
+			document.getElementById('status').textContent = '🎮 ' + data.message;
+			document.getElementById('status').style.background = '#d4edda';
+			return data.session.session_id;
+		})
+		.catch(error => {
+			console.error('❌ 연속 조깅 세션 시작 오류:', error);
+			return null;
+		});
+
+	// 하트비트 시작 (세션 시작 응답 후 첫 하트비트)
+	jogHeartbeatInterval = setInterval(() => sendJogHeartbeat(), JOG_HEARTBEAT_INTERVAL);
+}
 
-	console.log('⏱️  연속 조깅 인터벌 시작 (30ms)');
+// * 연속 조깅 하트비트 - 서버가 세션을 종료했으면(데드맨 등) 조깅 상태 해제
+function sendJogHeartbeat() {
+	const pending = jogSessionStart;
+	if (!pending) return;
+
+	pending.then(sessionId => {
+		if (!sessionId || !isJogging || pending !== jogSessionStart) return;
+
+		jogCommandCount++;
+		sendCommand('/api/jog/session/heartbeat', 'jog_heartbeat', { session_id: sessionId })
+			.then(data => {
+				if (!data.success && pending === jogSessionStart) {
+					console.warn('⛔ 연속 조깅 세션 종료됨:', data);
+					clearInterval(jogHeartbeatInterval);
+					jogHeartbeatInterval = null;
+					isJogging = false;
+					keyBusy = false;
+					document.getElementById('status').textContent = '⛔ ' + data.message;
+					document.getElementById('status').style.background = '#f8d7da';
+				}
+			})
+			.catch(error => console.error('❌ 하트비트 오류:', error));
+	});
 }
 
-// * 연속 조깅 중단 함수 - 원본 방식 개선
+// * 연속 조깅 중단 함수 - 세션 중단 (세션이 없으면 단발 중단 명령)
 function stopContinuousJog() {
-	const currentTime = performance.now();
-	const duration = currentTime - jogStartTime;
-	const avgInterval = jogCommandCount > 0 ? duration / jogCommandCount : 0;
+	const duration = performance.now() - jogStartTime;
 
 	console.log('🛑 연속 조깅 중단:', {
 		duration: duration.toFixed(1) + 'ms',
-		commandCount: jogCommandCount,
-		avgInterval: avgInterval.toFixed(1) + 'ms',
-		expectedInterval: '30ms',
-		performance: (avgInterval / 30 * 100).toFixed(1) + '%'
+		heartbeatCount: jogCommandCount
 	});
 
 	isJogging = false;
+	jogDirection = null;
 	keyBusy = false;  // 키 잠금 해제
 
-	if (continuousJogInterval) {
-		clearInterval(continuousJogInterval);
-		continuousJogInterval = null;
-		console.log('⏹️  연속 조깅 인터벌 정리 완료');
+	if (jogHeartbeatInterval) {
+		clearInterval(jogHeartbeatInterval);
+		jogHeartbeatInterval = null;
 	}
 
-	// 조깅 중단 명령을 서버에 전송 (원본 방식과 유사)
-	sendJogStop();
+	const pending = jogSessionStart;
+	jogSessionStart = null;
+	if (pending) {
+		// 시작 요청이 아직 진행 중이어도 응답을 기다렸다가 중단
+		pending.then(sessionId => sessionId ? sendJogSessionStop(sessionId) : sendJogStop());
+	} else {
+		sendJogStop();
+	}
 
 	// 연속 조깅이 끝났을 때 상태 업데이트
 	document.getElementById('status').textContent = '대기 중...';
 	document.getElementById('status').style.background = '';
 }
 
+// * 연속 조깅 세션 중단 요청 (서버가 중단 명령 전송)
+function sendJogSessionStop(sessionId) {
+	const fetchStartTime = performance.now();
+
+	sendCommand('/api/jog/session/stop', 'jog_stop', { session_id: sessionId })
+		.then(data => {
+			console.log('✅ 연속 조깅 세션 중단 응답:', {
+				response: data,
+				responseTime: (performance.now() - fetchStartTime).toFixed(1) + 'ms'
+			});
+			// 이미 종료된 세션이어도 안전하게 단발 중단 명령 전송
+			if (!data.success) {
+				sendJogStop();
+			}
+		})
+		.catch(error => {
+			console.error('❌ 연속 조깅 세션 중단 오류:', error);
+			sendJogStop();
+		});
+}
+
 // * 조깅 중단 명령 전송 함수 (원본의 jog(0) 방식)
 function sendJogStop() {
 	const currentTime = performance.now();
@@ -668,7 +987,9 @@ function sendJogStop() {
 		axis: axis,
 		dir: 'stop',      // 중단 신호
 		step: 0,          // 스텝 0
-		mode: mode
+		mode: mode,
+		seq: nextJogSeq(),  // 이 값 이하의 늦게 도착한 JOG 명령은 서버가 폐기
+		client_session: CLIENT_SESSION
 	};
 
 	console.log('🛑 조깅 중단 명령 전송:', {
@@ -680,14 +1001,7 @@ function sendJogStop() {
 	const fetchStartTime = performance.now();
 
 	// 중단 명령은 즉시 전송 (우선순위 높음)
-	fetch('/api/jog', {
-		method: 'POST',
-		headers: {
-			'Content-Type': 'application/json',
-		},
-		body: JSON.stringify(stopCommand)
-	})
-		.then(response => response.json())
+	sendCommand('/api/jog', 'jog', stopCommand)
 		.then(data => {
 			const responseTime = performance.now() - fetchStartTime;
 
@@ -708,6 +1022,53 @@ function sendJogStop() {
 		});
 }
 
+// * 전체 정지 - 대기/반복 중인 모든 JOG 명령 취소 후 즉시 중단 (선택적으로 JOG 비활성화)
+function allStop(source) {
+	// 브라우저 쪽 연속 조깅 상태 정리 (서버가 세션을 모두 종료함)
+	isJogging = false;
+	jogDirection = null;
+	keyBusy = false;
+	jogSessionStart = null;
+	if (jogHeartbeatInterval) {
+		clearInterval(jogHeartbeatInterval);
+		jogHeartbeatInterval = null;
+	}
+
+	const request = {
+		disable: document.getElementById('allStopDisable').checked,
+		source: source,
+		meta: { client_id: CLIENT_SESSION, user_agent: navigator.userAgent, timestamp: new Date().toISOString() }
+	};
+
+	console.log('🛑 전체 정지 요청:', request);
+
+	fetch('/api/stop', {
+		method: 'POST',
+		headers: {
+			'Content-Type': 'application/json',
+		},
+		body: JSON.stringify(request)
+	})
+		.then(response => response.json())
+		.then(record => {
+			console.log('🛑 전체 정지 결과:', record);
+			const statusEl = document.getElementById('status');
+			let text = (record.success ? '🛑 전체 정지 완료: ' : '❌ 전체 정지 실패: ') + record.message +
+				(record.error_code ? ' [' + record.error_code + ']' : '');
+			if (record.disable_requested && !record.jog_disabled) {
+				text += ' / ❌ JOG 비활성화 실패: ' + (record.disable_message || '') +
+					(record.disable_error_code ? ' [' + record.disable_error_code + ']' : '');
+			}
+			statusEl.textContent = text;
+			statusEl.style.background = record.success && (!record.disable_requested || record.jog_disabled) ? '#fff3cd' : '#f8d7da';
+		})
+		.catch(error => {
+			console.error('❌ 전체 정지 오류:', error);
+			document.getElementById('status').textContent = '❌ 전체 정지 통신 오류: ' + error;
+			document.getElementById('status').style.background = '#f8d7da';
+		});
+}
+
 // ...existing code...
 // (함수 simulateJointMove 등 나머지 함수 및 이벤트 핸들러 포함)
 
@@ -729,6 +1090,11 @@ document.addEventListener('keydown', function (event) {
 	}
 
 	switch (event.key) {
+		case 'Escape':
+			// 전체 정지는 입력 중에도 항상 동작
+			event.preventDefault();
+			allStop('keyboard');
+			break;
 		case 'ArrowLeft':
 		case '-':
 			// 텍스트 입력 중이 아닐 때만 조깅 명령 실행
@@ -758,8 +1124,11 @@ document.addEventListener('keydown', function (event) {
 			if (!isInputFocused) {
 				event.preventDefault();
 				const jointNum = parseInt(event.key);
-				document.getElementById('axisSelect').value = 'joint' + jointNum;
-				jogListChanged();
+				const axisSelect = document.getElementById('axisSelect');
+				if (jointNum <= axisSelect.options.length) {
+					axisSelect.selectedIndex = jointNum - 1;
+					jogListChanged();
+				}
 			}
 			break;
 	}
@@ -798,3 +1167,9 @@ document.getElementById('robot-canvas').addEventListener('wheel', function (even
 		stopContinuousJog();
 	}, 150);
 });
+
+// 로봇 모델에 맞춰 모드 버튼과 축 목록 초기화
+loadRobotModel();
+
+// 상태 스트림 연결 (실패하면 HTTP로 동작)
+connectWebSocket();